@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"skriptble.dev/podcast-tools/transcriber"
+)
+
+// runCheck decodes every audio file without transcribing and prints a
+// summary to w. Files are reduced to mono as transcription would: by their
+// own channel mix when set, otherwise by mix. The model path has already
+// been confirmed to exist. Audio read from standard input is skipped since
+// checking it would consume the stream.
+func runCheck(w io.Writer, audioFiles []transcriber.AudioFile, modelPath string, mix transcriber.ChannelMix, logger *slog.Logger) error {
+	fmt.Fprintf(w, "Model: %s\n", modelPath)
+
+	failed := 0
+	var total float64
+	for i, af := range audioFiles {
+		if af.Path == transcriber.StdinPath {
+			fmt.Fprintf(w, "  %d. %s (%s): skipped, standard input is not checked\n", i+1, af.Path, af.Speaker)
+			continue
+		}
+
+		fileMix := mix
+		if af.ChannelMix != "" {
+			fileMix = af.ChannelMix
+		}
+		samples, err := transcriber.LoadAudioChannel(af.Path, fileMix, logger)
+		if err != nil {
+			fmt.Fprintf(w, "  %d. %s (%s): FAILED: %v\n", i+1, af.Path, af.Speaker, err)
+			failed++
+			continue
+		}
+
+		seconds := float64(len(samples)) / transcriber.SampleRate
+		total += seconds
+		fmt.Fprintf(w, "  %d. %s (%s): OK, %.2f seconds\n", i+1, af.Path, af.Speaker, seconds)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d audio file(s) could not be decoded", failed, len(audioFiles))
+	}
+	fmt.Fprintf(w, "All checks passed: %d audio file(s), %.2f seconds of audio\n", len(audioFiles), total)
+	return nil
+}
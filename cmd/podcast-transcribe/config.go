@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runConfig is the JSON config file read with --config. Each setting
+// corresponds to the command-line flag of the same name.
+type runConfig struct {
+	Output       string       `json:"output"`
+	Format       string       `json:"format"`
+	Speakers     []string     `json:"speakers"`
+	Model        string       `json:"model"`
+	Language     string       `json:"language"`
+	Parallel     int          `json:"parallel"`
+	Transcribers int          `json:"transcribers"`
+	Files        []configFile `json:"files"`
+}
+
+// configFile is an audio file listed in a config file
+type configFile struct {
+	Path     string `json:"path"`
+	Speaker  string `json:"speaker"`
+	Language string `json:"language"`
+}
+
+// applyConfig reads the config file at path and sets each flag it configures
+// that was not given on the command line, in either its long or short form,
+// so command-line flags take precedence. It returns the audio files the
+// config lists, in the same form as readManifest.
+func applyConfig(path string) ([]string, []string, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to open config: %w", err)
+	}
+	defer file.Close()
+
+	var config runConfig
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	// Per-speaker output replaces the config file's output path
+	if explicit["output-dir"] {
+		explicit["output"] = true
+	}
+
+	settings := []struct {
+		long, short string
+		value       string
+	}{
+		{"output", "o", config.Output},
+		{"format", "f", config.Format},
+		{"speakers", "s", strings.Join(config.Speakers, ",")},
+		{"model", "m", config.Model},
+		{"language", "l", config.Language},
+		{"parallel", "p", formatConfigInt(config.Parallel)},
+		{"transcribers", "t", formatConfigInt(config.Transcribers)},
+	}
+	for _, setting := range settings {
+		if setting.value == "" || explicit[setting.long] || explicit[setting.short] {
+			continue
+		}
+		if err := flag.Set(setting.long, setting.value); err != nil {
+			return nil, nil, nil, fmt.Errorf("config %s: invalid %s: %w", path, setting.long, err)
+		}
+	}
+
+	var paths, speakers, languages []string
+	for i, f := range config.Files {
+		if f.Path == "" {
+			return nil, nil, nil, fmt.Errorf("config %s: file %d has no path", path, i+1)
+		}
+		paths = append(paths, f.Path)
+		speakers = append(speakers, f.Speaker)
+		languages = append(languages, f.Language)
+	}
+	return paths, speakers, languages, nil
+}
+
+// formatConfigInt formats a numeric config setting, with zero meaning unset
+func formatConfigInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
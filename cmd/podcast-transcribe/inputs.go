@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skriptble.dev/podcast-tools/transcriber"
+)
+
+// expandInputs resolves audio file arguments into file paths. Directories
+// expand to the supported audio files directly inside them and glob patterns
+// (e.g., "tracks/*.wav") are expanded with filepath.Glob, both in sorted
+// order. Other arguments are used as given. The returned speakers slice has
+// an entry for every path: the file name without its extension for files
+// found by expansion, and empty for files named explicitly.
+func expandInputs(args []string) ([]string, []string, error) {
+	var paths, speakers []string
+	for _, arg := range args {
+		var matches []string
+		switch {
+		case arg == transcriber.StdinPath:
+			paths = append(paths, arg)
+			speakers = append(speakers, "")
+			continue
+		case isDir(arg):
+			entries, err := os.ReadDir(arg)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read directory: %w", err)
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() && transcriber.IsSupportedAudioFile(entry.Name()) {
+					matches = append(matches, filepath.Join(arg, entry.Name()))
+				}
+			}
+			if len(matches) == 0 {
+				return nil, nil, fmt.Errorf("directory '%s' contains no supported audio files", arg)
+			}
+		case hasGlobMeta(arg):
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid pattern '%s': %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, nil, fmt.Errorf("pattern '%s' matched no files", arg)
+			}
+		default:
+			paths = append(paths, arg)
+			speakers = append(speakers, "")
+			continue
+		}
+
+		sort.Strings(matches)
+		for _, match := range matches {
+			paths = append(paths, match)
+			speakers = append(speakers, speakerFromFilename(match))
+		}
+	}
+	return paths, speakers, nil
+}
+
+// speakerFromFilename derives a speaker label from an audio file name,
+// e.g. "tracks/alice.wav" becomes "alice"
+func speakerFromFilename(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// isDir reports whether path names an existing directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// hasGlobMeta reports whether path contains filepath.Match metacharacters.
+// Paths that exist as given are never treated as patterns.
+func hasGlobMeta(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return false
+	}
+	return strings.ContainsAny(path, `*?[`)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// newConsoleLogger returns a logger that writes plain lines to the console,
+// keeping the tool's output readable: warnings and errors go to errOut, and
+// informational and debug records go to out only when verbose is set
+func newConsoleLogger(out, errOut io.Writer, verbose bool) *slog.Logger {
+	return slog.New(&consoleHandler{out: out, errOut: errOut, verbose: verbose, mu: new(sync.Mutex)})
+}
+
+// consoleHandler is the slog.Handler behind newConsoleLogger. Each record is
+// written as its message followed by its attributes as key=value pairs, with
+// debug records indented beneath the message they add detail to.
+type consoleHandler struct {
+	out     io.Writer
+	errOut  io.Writer
+	verbose bool
+	attrs   []slog.Attr
+	group   string      // Prefix for attribute keys added after WithGroup
+	mu      *sync.Mutex // Shared by derived handlers so lines are not interleaved
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.verbose || level >= slog.LevelWarn
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	switch {
+	case r.Level >= slog.LevelError:
+		buf.WriteString("Error: ")
+	case r.Level >= slog.LevelWarn:
+		buf.WriteString("Warning: ")
+	case r.Level < slog.LevelInfo:
+		buf.WriteString("  ")
+	}
+	buf.WriteString(r.Message)
+	for _, a := range h.attrs {
+		writeAttr(&buf, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&buf, h.group, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	w := h.out
+	if r.Level >= slog.LevelWarn {
+		w = h.errOut
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	h2.attrs = append(h2.attrs, h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + a.Key
+		}
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// writeAttr appends " key=value" to buf, quoting values that contain spaces
+// and flattening groups into dotted keys
+func writeAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeAttr(buf, prefix, ga)
+		}
+		return
+	}
+
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(prefix + a.Key)
+	buf.WriteByte('=')
+	buf.WriteString(value)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConsoleLoggerRouting(t *testing.T) {
+	tests := []struct {
+		name    string
+		verbose bool
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "quiet",
+			verbose: false,
+			wantErr: "Warning: Low memory free=\"2 GB\"\n",
+		},
+		{
+			name:    "verbose",
+			verbose: true,
+			wantOut: "Transcribing file=a.wav speaker=Alice\n  Resampled rate=16000\n",
+			wantErr: "Warning: Low memory free=\"2 GB\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			logger := newConsoleLogger(&out, &errOut, tt.verbose)
+			logger.Info("Transcribing", "file", "a.wav", "speaker", "Alice")
+			logger.Debug("Resampled", "rate", 16000)
+			logger.Warn("Low memory", "free", "2 GB")
+
+			if out.String() != tt.wantOut {
+				t.Errorf("out = %q, want %q", out.String(), tt.wantOut)
+			}
+			if errOut.String() != tt.wantErr {
+				t.Errorf("errOut = %q, want %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"skriptble.dev/podcast-tools/formats"
+	"skriptble.dev/podcast-tools/models"
 	"skriptble.dev/podcast-tools/transcriber"
 )
 
@@ -17,31 +25,144 @@ const (
 
 var (
 	// Required flags
-	outputPath   = flag.String("output", "", "Output file path (required)")
-	outputShort  = flag.String("o", "", "Output file path (short form)")
-	formatType   = flag.String("format", "", "Output format: txt, srt, vtt, json (required)")
-	formatShort  = flag.String("f", "", "Output format (short form)")
+	outputPath  = flag.String("output", "", "Output file path (required)")
+	outputShort = flag.String("o", "", "Output file path (short form)")
+	outputDir   = flag.String("output-dir", "", "Write one file per speaker into this directory instead of --output")
+	outputTmpl  = flag.String("output-template", defaultOutputTemplate, "File name of each speaker's file in --output-dir; {speaker} and {format} are replaced")
+	formatType  = flag.String("format", "", "Output format: txt, srt, vtt, json, edl, ssml, csv, md, labels, ttml, html, ass, jsonl, ansi (default: from output extension)")
+	formatShort = flag.String("f", "", "Output format (short form)")
 
 	// Optional flags
-	speakers     = flag.String("speakers", "", "Comma-separated list of speaker names")
-	speakersShort = flag.String("s", "", "Speaker names (short form)")
-	model        = flag.String("model", defaultModel, "Whisper model: tiny, base, small, medium, large, large-v3")
-	modelShort   = flag.String("m", "", "Whisper model (short form)")
-	modelPath    = flag.String("model-path", "", "Path to Whisper model file (auto-detect if not provided)")
-	language     = flag.String("language", "auto", "Language code (e.g., 'en', 'es') or 'auto' for detection")
-	languageShort = flag.String("l", "", "Language code (short form)")
-	parallel     = flag.Int("parallel", 0, "Number of parallel transcription jobs (default: number of CPU cores)")
-	parallelShort = flag.Int("p", 0, "Parallel jobs (short form)")
-	transcribers = flag.Int("transcribers", 0, "Number of transcriber instances for parallel processing (default: 1, each ~3GB memory)")
+	speakers          = flag.String("speakers", "", "Comma-separated list of speaker names")
+	speakersShort     = flag.String("s", "", "Speaker names (short form)")
+	speakerRoles      = flag.String("speaker-roles", "", "Comma-separated speaker roles (e.g., 'Alice=Host,Bob=Guest')")
+	showRoles         = flag.Bool("show-roles", false, "Include speaker roles in text headings")
+	model             = flag.String("model", defaultModel, "Whisper model: tiny, base, small, medium, large, large-v3")
+	modelShort        = flag.String("m", "", "Whisper model (short form)")
+	modelPath         = flag.String("model-path", "", "Path or http(s) URL of Whisper model file (auto-detect if not provided)")
+	modelSHA256       = flag.String("model-sha256", "", "Expected SHA-256 checksum of a downloaded model")
+	download          = flag.Bool("download", false, "Download the model into the model directory if it is missing")
+	language          = flag.String("language", "auto", "Language code (e.g., 'en', 'es') or 'auto' for detection")
+	languageShort     = flag.String("l", "", "Language code (short form)")
+	prompt            = flag.String("prompt", "", "Initial prompt to bias whisper toward proper nouns and jargon")
+	translate         = flag.Bool("translate", false, "Translate speech to English (output is English regardless of source language)")
+	normalize         = flag.Bool("normalize", false, "Scale quiet audio up to a 0.95 peak before transcription")
+	trimSilence       = flag.Bool("trim-silence", false, "Skip leading and trailing silence in each track when transcribing")
+	silenceThreshold  = flag.Float64("silence-threshold", transcriber.DefaultSilenceThreshold, "RMS level (0-1) below which audio counts as silence for --trim-silence")
+	channelMix        = flag.String("channel-mix", string(transcriber.MixAverage), "How to reduce multichannel audio to mono: average, left, right, or a channel number")
+	beamSize          = flag.Int("beam-size", 0, "Beam search width; only 0 (greedy decoding) is supported")
+	temperature       = flag.Float64("temperature", 0, "Sampling temperature (default: 0)")
+	parallel          = flag.Int("parallel", 0, "Number of parallel transcription jobs (default: number of CPU cores)")
+	parallelShort     = flag.Int("p", 0, "Parallel jobs (short form)")
+	transcribers      = flag.Int("transcribers", 0, "Number of transcriber instances for parallel processing (default: 1, each ~3GB memory for large models)")
 	transcribersShort = flag.Int("t", 0, "Transcriber instances (short form)")
-	verbose      = flag.Bool("verbose", false, "Enable verbose logging")
-	verboseShort = flag.Bool("v", false, "Verbose logging (short form)")
+	allowOvercommit   = flag.Bool("allow-overcommit", false, "Create all --transcribers instances even if they may not fit in available memory")
+	loadConcurrency   = flag.Int("load-concurrency", 0, "Number of transcriber instances to load at once (default: 1)")
+	maxRetries        = flag.Int("retries", 0, "Number of times to retry a failed transcription before giving up on the file")
+	cacheDir          = flag.String("cache-dir", "", "Cache transcriptions in this directory and reuse them when the audio and settings are unchanged")
+	useCache          = flag.Bool("cache", false, "Cache transcriptions in the user cache directory, as --cache-dir does")
+	fileTimeout       = flag.Duration("file-timeout", 0, "Give up on a file whose transcription takes longer than this, e.g. 30m (0 = no limit)")
+	chunkSeconds      = flag.Int("chunk-seconds", 0, "Split each file into chunks of this many seconds transcribed in parallel (0 = whole files)")
+	edlMinSilence     = flag.Float64("edl-min-silence", formats.DefaultEDLMinSilence, "Minimum silence in seconds to cut in EDL output")
+	srtWordsPerCue    = flag.Int("srt-words-per-cue", 0, "Split SRT cues every N words using word timestamps (0 = one cue per segment)")
+	srtBOM            = flag.Bool("srt-bom", false, "Start SRT output with a UTF-8 byte order mark, for legacy players")
+	maxCueSeconds     = flag.Float64("max-cue-seconds", 0, "Split SRT/VTT/ASS cues longer than this many seconds (0 = no limit)")
+	splitOnSentences  = flag.Bool("split-on-sentences", false, "Prefer sentence ends when splitting long cues with --max-cue-seconds")
+	maxLineLength     = flag.Int("max-line-length", 0, "Wrap SRT/VTT/ASS cue text at this many characters per line (0 = no wrapping)")
+	vttNote           = flag.Bool("vtt-note", false, "Add a NOTE block with the duration and speakers to VTT output")
+	vttVoiceClasses   = flag.Bool("vtt-voice-classes", false, "Add a CSS class derived from the speaker name to VTT voice tags")
+	txtTimestamps     = flag.Bool("txt-timestamps", false, "Prefix each speaker turn in text output with its start time")
+	minCueGap         = flag.Float64("min-cue-gap", 0, "Minimum seconds between consecutive SRT/VTT cues, e.g. 0.001 (0 = off)")
+	mdAnchors         = flag.Bool("md-anchors", false, "Render Markdown timestamps as linkable anchors")
+	sourceIDs         = flag.Bool("source-ids", false, "Include whisper source segment indices in JSON output")
+	suppressBleed     = flag.Bool("suppress-bleed", false, "Drop duplicate segments picked up by another speaker's microphone")
+	dedupe            = flag.Bool("dedupe", false, "Collapse runs of identical consecutive segments from one speaker, from whisper repetition loops")
+	removeFillers     = flag.Bool("remove-fillers", false, "Remove filler words such as 'um' and 'uh' from segment text")
+	mergeGap          = flag.Float64("merge-gap", 0, "Merge consecutive same-speaker segments separated by at most this many seconds (0 = off)")
+	maxSegmentSecs    = flag.Float64("max-segment-duration", 0, "Split segments longer than this many seconds at word boundaries (0 = no limit)")
+	since             = flag.String("since", "", "Keep only segments ending after this time (HH:MM:SS)")
+	until             = flag.String("until", "", "Keep only segments starting before this time (HH:MM:SS)")
+	clipWindow        = flag.Bool("clip-window", false, "Clip segments at the --since/--until edges to the window")
+	trimSilenceEdges  = flag.Bool("trim-silence-edges", false, "Shift timestamps so the transcript starts at the first spoken word")
+	trimTrailing      = flag.Bool("trim-trailing-silence", false, "With --trim-silence-edges, also end the transcript at the last spoken word")
+	sortOrder         = flag.String("sort", "time", "Segment order: 'time' (chronological) or 'speaker' (grouped by speaker)")
+	offset            = flag.Float64("offset", 0, "Seconds to add to every timestamp (negative to subtract), applied last")
+	rename            = flag.String("rename", "", "Comma-separated speaker renames applied before formatting (e.g., 'Speaker 1=Alice,Speaker 2=Bob')")
+	allowDuplicates   = flag.Bool("allow-duplicates", false, "Warn instead of failing when audio files or speaker labels are repeated")
+	strictAudio       = flag.Bool("strict-audio", false, "Fail if audio files differ in sample rate or channel count")
+	keepGoing         = flag.Bool("keep-going", false, "Write a partial transcript when some files fail, still exiting non-zero")
+	configPath        = flag.String("config", "", "JSON file of settings and audio files; command-line flags take precedence")
+	manifest          = flag.String("manifest", "", "File listing audio files to transcribe, one 'path[,speaker[,language]]' per line")
+	splitStereo       = flag.String("split-stereo", "", "Transcribe the left and right channels of one stereo file as two speakers (e.g., \"Alice,Bob\")")
+	shard             = flag.String("shard", "", "Transcribe only shard i of N of the audio files (e.g., '2/4')")
+	mergeShards       = flag.Bool("merge-shards", false, "Merge per-shard JSON transcripts given as arguments instead of transcribing")
+	warnOverlaps      = flag.Bool("warn-overlaps", false, "Print segments from different speakers that overlap in time")
+	showStats         = flag.Bool("stats", false, "Print per-speaker segment, word, and speaking time statistics after transcription")
+	benchmark         = flag.Bool("benchmark", false, "Print each file's real-time factor, the overall real-time factor, and model load time after transcription")
+	check             = flag.Bool("check", false, "Validate arguments, model, and audio decoding without transcribing")
+	jsonSchema        = flag.Bool("json-schema", false, "Print the JSON Schema for json output and exit")
+	listModelsFlag    = flag.Bool("list-models", false, "List standard and downloaded models in the model directory and exit")
+	probe             = flag.Bool("probe", false, "Load the model, print whether it is multilingual and the languages it supports, and exit")
+	verbose           = flag.Bool("verbose", false, "Enable verbose logging")
+	verboseShort      = flag.Bool("v", false, "Verbose logging (short form)")
 )
 
+// stdoutPath is the --output value that writes the transcript to standard output
+const stdoutPath = "-"
+
 func main() {
 	flag.Usage = printUsage
 	flag.Parse()
 
+	// Print the JSON output schema without transcribing
+	if *jsonSchema {
+		schema, err := formats.JSONSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(schema)
+		return
+	}
+
+	// Show which models are downloaded without transcribing
+	if *listModelsFlag {
+		if err := listModels(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Fill in settings not given on the command line from the config file
+	var configFiles, configSpeakers, configLanguages []string
+	if *configPath != "" {
+		var err error
+		configFiles, configSpeakers, configLanguages, err = applyConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Load the model and print what it supports without transcribing
+	if *probe {
+		modelName := getStringFlag(*model, *modelShort)
+		if modelName == "" {
+			modelName = defaultModel
+		}
+		logger := newConsoleLogger(os.Stdout, os.Stderr, *verbose || *verboseShort)
+		modelFilePath, err := resolveModelPath(os.Stdout, modelName, *modelPath, *modelSHA256, *download, logger)
+		if err == nil {
+			err = probeModel(os.Stdout, modelFilePath, logger)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Get non-flag arguments (audio files)
 	audioFiles := flag.Args()
 
@@ -49,24 +170,113 @@ func main() {
 	output := getStringFlag(*outputPath, *outputShort)
 	format := getStringFlag(*formatType, *formatShort)
 
-	if output == "" {
+	if output == "" && *outputDir == "" {
 		fmt.Fprintln(os.Stderr, "Error: --output/-o flag is required")
 		printUsage()
 		os.Exit(1)
 	}
+	if output != "" && *outputDir != "" {
+		fmt.Fprintln(os.Stderr, "Error: --output and --output-dir cannot be combined")
+		os.Exit(1)
+	}
+	if *outputDir != "" {
+		if err := checkOutputTemplate(*outputTmpl); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --output-template: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	destination := outputName(output)
+	if *outputDir != "" {
+		destination = *outputDir
+	}
 
+	// Infer the format from the output extension when not given explicitly
 	if format == "" {
-		fmt.Fprintln(os.Stderr, "Error: --format/-f flag is required")
-		printUsage()
-		os.Exit(1)
+		name := output
+		if *outputDir != "" {
+			name = *outputTmpl
+		}
+		detected, ok := formats.FormatFromExtension(name)
+		if !ok {
+			fmt.Fprintln(os.Stderr, "Error: --format/-f flag is required when the output extension is not a known format")
+			printUsage()
+			os.Exit(1)
+		}
+		format = string(detected)
+	}
+	format = strings.ToLower(format)
+
+	// Informational messages go to info, which is standard error when the
+	// transcript itself goes to standard output so they cannot corrupt it
+	var info io.Writer = os.Stdout
+	if output == stdoutPath {
+		// Colors are only for terminals; piped previews get plain text
+		if formats.Format(format) == formats.FormatANSI && !isTerminal(os.Stdout) {
+			format = string(formats.FormatTXT)
+		}
+		info = os.Stderr
 	}
 
 	// Validate format
 	if !formats.IsValidFormat(format) {
-		fmt.Fprintf(os.Stderr, "Error: invalid format '%s'. Valid formats: txt, srt, vtt, json\n", format)
+		fmt.Fprintf(os.Stderr, "Error: invalid format '%s'. Valid formats: %s\n", format, validFormatList())
 		os.Exit(1)
 	}
 
+	// Subtitle cues must stay in time order for players
+	if *sortOrder == "speaker" {
+		switch formats.Format(format) {
+		case formats.FormatSRT, formats.FormatVTT, formats.FormatASS, formats.FormatTTML:
+			fmt.Fprintf(os.Stderr, "Error: --sort speaker cannot be used with %s output; subtitles stay chronological\n", format)
+			os.Exit(1)
+		}
+	}
+
+	// Combine per-shard JSON transcripts instead of transcribing
+	if *mergeShards {
+		if *outputDir != "" {
+			fmt.Fprintln(os.Stderr, "Error: --output-dir cannot be used with --merge-shards; merge to one file first")
+			os.Exit(1)
+		}
+		if err := runMergeShards(info, audioFiles, output, formats.Format(format), *verbose || *verboseShort); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Read audio files from the manifest
+	var manifestSpeakers, manifestLanguages []string
+	if *manifest != "" {
+		if len(audioFiles) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: audio files cannot be given as arguments with --manifest")
+			os.Exit(1)
+		}
+		var err error
+		audioFiles, manifestSpeakers, manifestLanguages, err = readManifest(*manifest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Expand directories and glob patterns, labeling those files by name
+	var expandedSpeakers []string
+	if *manifest == "" {
+		var err error
+		audioFiles, expandedSpeakers, err = expandInputs(audioFiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Fall back to the audio files listed in the config file, which carry
+	// speakers and languages like a manifest
+	if len(audioFiles) == 0 && len(configFiles) > 0 {
+		audioFiles, manifestSpeakers, manifestLanguages = configFiles, configSpeakers, configLanguages
+	}
+
 	// Validate audio files
 	if len(audioFiles) == 0 {
 		fmt.Fprintln(os.Stderr, "Error: at least one audio file is required")
@@ -87,6 +297,23 @@ func main() {
 	parallelJobs := getIntFlag(*parallel, *parallelShort)
 	numTranscribers := getIntFlag(*transcribers, *transcribersShort)
 	isVerbose := *verbose || *verboseShort
+	logger := newConsoleLogger(info, os.Stderr, isVerbose)
+
+	// Transcribe each channel of one mixed stereo file as its own speaker
+	var channelMixes []transcriber.ChannelMix
+	if *splitStereo != "" {
+		names, err := parseSplitStereo(*splitStereo, audioFiles, speakerNames)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --split-stereo: %v\n", err)
+			os.Exit(1)
+		}
+		audioFiles = []string{audioFiles[0], audioFiles[0]}
+		speakerNames = strings.Join(names, ",")
+		channelMixes = []transcriber.ChannelMix{transcriber.MixLeft, transcriber.MixRight}
+		if len(manifestLanguages) == 1 {
+			manifestLanguages = append(manifestLanguages, manifestLanguages[0])
+		}
+	}
 
 	// Parse speaker names
 	var speakerLabels []string
@@ -101,102 +328,447 @@ func main() {
 	// Generate default speaker labels if not provided
 	if len(speakerLabels) == 0 {
 		speakerLabels = transcriber.GenerateDefaultSpeakerLabels(len(audioFiles))
+		for i, name := range append(manifestSpeakers, expandedSpeakers...) {
+			if name != "" {
+				speakerLabels[i] = name
+			}
+		}
 	} else if len(speakerLabels) != len(audioFiles) {
 		fmt.Fprintf(os.Stderr, "Error: number of speaker labels (%d) doesn't match number of audio files (%d)\n",
 			len(speakerLabels), len(audioFiles))
 		os.Exit(1)
 	}
 
-	// Determine model path
-	modelFilePath := *modelPath
-	if modelFilePath == "" {
-		modelFilePath = transcriber.GetDefaultModelPath(modelName)
-		if modelFilePath == "" {
-			fmt.Fprintln(os.Stderr, "Error: could not determine home directory for model cache")
+	// Parse speaker roles
+	roles, err := parseKeyValueList(*speakerRoles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --speaker-roles: %v\n", err)
+		os.Exit(1)
+	}
+	for name := range roles {
+		if !containsString(speakerLabels, name) {
+			fmt.Fprintf(os.Stderr, "Error: --speaker-roles names unknown speaker '%s'\n", name)
 			os.Exit(1)
 		}
 	}
 
-	// Check if model exists
-	if _, err := os.Stat(modelFilePath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Whisper model not found at %s\n", modelFilePath)
-		fmt.Fprintln(os.Stderr, "\nPlease download the model from:")
-		fmt.Fprintf(os.Stderr, "  https://huggingface.co/ggerganov/whisper.cpp/tree/main\n")
-		fmt.Fprintf(os.Stderr, "\nExpected model file: ggml-%s.bin\n", modelName)
-		fmt.Fprintf(os.Stderr, "Default location: %s\n", filepath.Dir(modelFilePath))
+	transforms, err := transformsFromFlags()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if isVerbose {
-		fmt.Printf("Podcast Transcription Tool\n")
-		fmt.Printf("==========================\n")
-		fmt.Printf("Audio files: %d\n", len(audioFiles))
-		for i, file := range audioFiles {
-			fmt.Printf("  %d. %s (%s)\n", i+1, file, speakerLabels[i])
-		}
-		fmt.Printf("Output: %s\n", output)
-		fmt.Printf("Format: %s\n", format)
-		fmt.Printf("Model: %s (%s)\n", modelName, modelFilePath)
-		fmt.Printf("Language: %s\n", lang)
-		fmt.Printf("Parallel jobs: %d\n", parallelJobs)
-		fmt.Printf("Transcriber instances: %d\n", numTranscribers)
-		fmt.Println()
-	}
-
 	// Prepare audio files with speaker labels
 	audioFileList := make([]transcriber.AudioFile, len(audioFiles))
 	for i, file := range audioFiles {
 		audioFileList[i] = transcriber.AudioFile{
 			Path:    file,
 			Speaker: speakerLabels[i],
+			Role:    roles[speakerLabels[i]],
+		}
+		if i < len(manifestLanguages) {
+			audioFileList[i].Language = manifestLanguages[i]
+		}
+		if i < len(channelMixes) {
+			audioFileList[i].ChannelMix = channelMixes[i]
+		}
+	}
+
+	// Keep only this machine's share of the files
+	if *shard != "" {
+		if formats.Format(format) != formats.FormatJSON {
+			fmt.Fprintln(os.Stderr, "Error: --shard requires json output so shards can be merged with --merge-shards")
+			os.Exit(1)
+		}
+		index, count, err := parseShard(*shard)
+		if err == nil {
+			audioFileList, err = transcriber.ShardAudioFiles(audioFileList, index, count)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --shard: %v\n", err)
+			os.Exit(1)
+		}
+		if len(audioFileList) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: shard %s has no audio files\n", *shard)
+			os.Exit(1)
+		}
+	}
+
+	// Check that the output can be written before spending time on transcription
+	if *outputDir != "" {
+		err = checkOutputDirWritable(*outputDir)
+	} else {
+		err = checkOutputWritable(output)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Determine model path, downloading the model if needed
+	modelFilePath, err := resolveModelPath(info, modelName, *modelPath, *modelSHA256, *download, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if isVerbose {
+		fmt.Fprintf(info, "Podcast Transcription Tool\n")
+		fmt.Fprintf(info, "==========================\n")
+		if *shard != "" {
+			fmt.Fprintf(info, "Shard: %s\n", *shard)
+		}
+		fmt.Fprintf(info, "Audio files: %d\n", len(audioFileList))
+		for i, af := range audioFileList {
+			details := af.Speaker
+			if af.ChannelMix != "" {
+				details += ", channel: " + string(af.ChannelMix)
+			}
+			if af.Language != "" {
+				details += ", language: " + af.Language
+			}
+			fmt.Fprintf(info, "  %d. %s (%s)\n", i+1, af.Path, details)
+			// Files that can't be read are reported when they are decoded
+			if audio, err := transcriber.ReadAudioInfo(af.Path); err == nil {
+				fmt.Fprintf(info, "     %s, %s\n", audio, models.FormatTimestamp(audio.Duration))
+			}
 		}
+		fmt.Fprintf(info, "Output: %s\n", destination)
+		if *outputDir != "" {
+			fmt.Fprintf(info, "Output files: %s\n", *outputTmpl)
+		}
+		fmt.Fprintf(info, "Format: %s\n", format)
+		fmt.Fprintf(info, "Model: %s (%s)\n", modelName, modelFilePath)
+		fmt.Fprintf(info, "Language: %s\n", lang)
+		fmt.Fprintf(info, "Parallel jobs: %d\n", parallelJobs)
+		fmt.Fprintf(info, "Transcriber instances: %d\n", numTranscribers)
+		fmt.Fprintln(info)
 	}
 
 	// Validate audio files
 	if err := transcriber.ValidateAudioFiles(audioFileList); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var dupErr *transcriber.DuplicateError
+		if !errors.As(err, &dupErr) || !*allowDuplicates {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if dupErr != nil {
+				fmt.Fprintln(os.Stderr, "Use --allow-duplicates to proceed anyway")
+			}
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	// Tracks from one session should share a format; a mismatch usually means a wrong file
+	if *strictAudio {
+		if err := transcriber.CheckAudioFormats(audioFileList); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *beamSize != 0 {
+		fmt.Fprintln(os.Stderr, "Error: --beam-size is not supported: the whisper Go binding only decodes greedily")
+		os.Exit(1)
+	}
+	if *temperature < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --temperature must not be negative")
+		os.Exit(1)
+	}
+	if *silenceThreshold <= 0 || *silenceThreshold >= 1 {
+		fmt.Fprintln(os.Stderr, "Error: --silence-threshold must be between 0 and 1")
 		os.Exit(1)
 	}
+	mix, err := transcriber.ParseChannelMix(*channelMix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --channel-mix: %v\n", err)
+		os.Exit(1)
+	}
+	// Caching is opt-in, since entries accumulate on disk until deleted
+	cache := *cacheDir
+	if cache == "" && *useCache {
+		cache = transcriber.DefaultCacheDir()
+	}
+
+	// Stop after validating everything that can be checked without whisper
+	if *check {
+		if err := runCheck(info, audioFileList, modelFilePath, mix, logger); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Configure processing
 	config := transcriber.ProcessConfig{
 		AudioFiles: audioFileList,
 		WhisperConfig: transcriber.WhisperConfig{
-			ModelPath: modelFilePath,
-			Language:  lang,
-			Verbose:   isVerbose,
+			ModelPath:        modelFilePath,
+			Language:         lang,
+			BeamSize:         *beamSize,
+			Temperature:      float32(*temperature),
+			Translate:        *translate,
+			InitialPrompt:    *prompt,
+			Normalize:        *normalize,
+			TrimSilence:      *trimSilence,
+			SilenceThreshold: float32(*silenceThreshold),
+			ChannelMix:       mix,
+			CacheDir:         cache,
+			Logger:           logger,
 		},
 		MaxParallel:     parallelJobs,
 		NumTranscribers: numTranscribers,
+		AllowOvercommit: *allowOvercommit,
+		LoadConcurrency: *loadConcurrency,
+		ChunkSeconds:    *chunkSeconds,
+		MaxRetries:      *maxRetries,
+		PerFileTimeout:  *fileTimeout,
+		SuppressBleed:   *suppressBleed,
+		Transforms:      transforms,
+		ContinueOnError: *keepGoing,
 	}
 
-	// Process files
-	transcript, err := transcriber.ProcessFiles(config)
-	if err != nil {
+	// Keep the run's timings for --benchmark
+	var timings transcriber.Timings
+	if *benchmark {
+		config.Timings = func(t transcriber.Timings) {
+			timings = t
+		}
+	}
+
+	// Process files, stopping cleanly on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	transcript, err := transcriber.ProcessFilesContext(ctx, config)
+	stop()
+	var partialErr *transcriber.PartialError
+	if errors.As(err, &partialErr) {
+		fmt.Fprintln(os.Stderr, "Error: some files failed to transcribe:")
+		for _, fileErr := range partialErr.Errors {
+			fmt.Fprintf(os.Stderr, "  - %v\n", fileErr)
+		}
+	} else if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Format output
-	formattedOutput, err := formats.FormatTranscript(transcript, formats.Format(format))
+	// Report crosstalk that may need manual trimming
+	if *warnOverlaps {
+		printOverlaps(transcript)
+	}
+
+	// Format output straight into the output file, or one file per speaker
+	var written []string
+	if *outputDir != "" {
+		written, err = writeSpeakerFiles(*outputDir, *outputTmpl, transcript, formats.Format(format))
+	} else {
+		err = writeOutput(output, transcript, formats.Format(format))
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Write output
-	if err := os.WriteFile(output, []byte(formattedOutput), 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+	// Partial transcripts are written, but the run still counts as failed
+	if partialErr != nil {
+		fmt.Fprintf(os.Stderr, "Partial transcript of %d file(s) written to %s\n",
+			len(audioFileList)-len(partialErr.Errors), destination)
 		os.Exit(1)
 	}
 
 	if isVerbose {
-		fmt.Printf("\n✓ Transcription complete!\n")
-		fmt.Printf("Output written to: %s\n", output)
-		fmt.Printf("Total segments: %d\n", len(transcript.Segments))
-		fmt.Printf("Total duration: %.2f seconds\n", transcript.Duration())
+		fmt.Fprintf(info, "\n✓ Transcription complete!\n")
+		fmt.Fprintf(info, "Output written to: %s\n", destination)
+		for _, path := range written {
+			fmt.Fprintf(info, "  %s\n", path)
+		}
+		fmt.Fprintf(info, "Total segments: %d\n", len(transcript.Segments))
+		fmt.Fprintf(info, "Total duration: %.2f seconds\n", transcript.Duration())
 	} else {
-		fmt.Printf("Transcription complete: %s\n", output)
+		fmt.Fprintf(info, "Transcription complete: %s\n", destination)
+	}
+
+	if *showStats {
+		fmt.Fprintln(info)
+		printStats(info, transcript)
+	}
+
+	if *benchmark {
+		fmt.Fprintln(info)
+		printBenchmark(info, timings, audioFileList)
+	}
+}
+
+// writeOutput formats transcript into the file at path, streaming formats
+// that support it rather than building the whole output in memory. Output
+// goes to a temporary file that replaces path only once it is complete, so
+// an existing file is left untouched if formatting fails. A path of
+// stdoutPath writes to standard output instead.
+func writeOutput(path string, transcript *models.Transcript, format formats.Format) error {
+	if path == stdoutPath {
+		w := bufio.NewWriter(os.Stdout)
+		err := formats.FormatTranscriptToWithOptions(w, transcript, format, formatOptionsFromFlags())
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	w := bufio.NewWriter(file)
+	err = formats.FormatTranscriptToWithOptions(w, transcript, format, formatOptionsFromFlags())
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = file.Chmod(0644)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a pipe
+// or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// parseSplitStereo validates --split-stereo and returns its two speaker
+// names, left channel first. It requires exactly one audio file, which must
+// be a real file since raw standard input is already mono, and conflicts
+// with --speakers.
+func parseSplitStereo(spec string, audioFiles []string, speakerNames string) ([]string, error) {
+	if speakerNames != "" {
+		return nil, fmt.Errorf("names the speakers itself and cannot be combined with --speakers")
+	}
+	if len(audioFiles) != 1 {
+		return nil, fmt.Errorf("requires exactly one audio file, got %d", len(audioFiles))
+	}
+	if audioFiles[0] == transcriber.StdinPath {
+		return nil, fmt.Errorf("cannot split standard input, which is mono")
+	}
+
+	names := strings.Split(spec, ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+	}
+	if len(names) != 2 || names[0] == "" || names[1] == "" {
+		return nil, fmt.Errorf("expected two speaker names as \"left,right\", got '%s'", spec)
+	}
+	return names, nil
+}
+
+// outputName describes the output path in messages
+func outputName(output string) string {
+	if output == stdoutPath {
+		return "standard output"
+	}
+	return output
+}
+
+// printOverlaps warns about segments from different speakers that overlap
+func printOverlaps(transcript *models.Transcript) {
+	overlaps := transcript.Overlaps()
+	if len(overlaps) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %d overlapping segment pair(s)\n", len(overlaps))
+	for _, pair := range overlaps {
+		a, b := transcript.Segments[pair[0]], transcript.Segments[pair[1]]
+		fmt.Fprintf(os.Stderr, "  %s-%s %s / %s-%s %s\n",
+			models.FormatTimestamp(a.StartTime), models.FormatTimestamp(a.EndTime), a.Speaker,
+			models.FormatTimestamp(b.StartTime), models.FormatTimestamp(b.EndTime), b.Speaker)
+	}
+}
+
+// transformsFromFlags builds the ordered post-processing steps requested on the command line
+func transformsFromFlags() ([]transcriber.Transform, error) {
+	var transforms []transcriber.Transform
+	names, err := parseKeyValueList(*rename)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --rename: %w", err)
+	}
+	if len(names) > 0 {
+		transforms = append(transforms, transcriber.RenameSpeakers{Names: names})
+	}
+	if *dedupe {
+		transforms = append(transforms, transcriber.RemoveRepetitions{MaxRepeats: 1})
+	}
+	if *removeFillers {
+		transforms = append(transforms, transcriber.RemoveFillers{})
+	}
+	if *mergeGap > 0 {
+		transforms = append(transforms, transcriber.MergeAdjacentSpeakers{MaxGap: *mergeGap})
+	}
+	if *maxSegmentSecs > 0 {
+		transforms = append(transforms, transcriber.SplitLongSegments{MaxSeconds: *maxSegmentSecs})
+	}
+	if *since != "" || *until != "" {
+		window := transcriber.Window{End: math.Inf(1), Clip: *clipWindow}
+		if *since != "" {
+			if window.Start, err = parseTimecode(*since); err != nil {
+				return nil, fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+		if *until != "" {
+			if window.End, err = parseTimecode(*until); err != nil {
+				return nil, fmt.Errorf("invalid --until: %w", err)
+			}
+		}
+		if window.End <= window.Start {
+			return nil, fmt.Errorf("--until must be after --since")
+		}
+		transforms = append(transforms, window)
+	}
+	if *trimSilenceEdges {
+		transforms = append(transforms, transcriber.TrimSilenceEdges{Trailing: *trimTrailing})
+	}
+	if *offset != 0 {
+		transforms = append(transforms, transcriber.Shift{Seconds: *offset})
+	}
+	switch *sortOrder {
+	case "time":
+	case "speaker":
+		transforms = append(transforms, transcriber.SortBySpeaker{})
+	default:
+		return nil, fmt.Errorf("invalid --sort '%s': expected time or speaker", *sortOrder)
+	}
+	return transforms, nil
+}
+
+// formatOptionsFromFlags collects the formatter options set on the command line
+func formatOptionsFromFlags() formats.Options {
+	return formats.Options{
+		EDLMinSilence:    *edlMinSilence,
+		IncludeSourceIDs: *sourceIDs,
+		SRTWordsPerCue:   *srtWordsPerCue,
+		SRTByteOrderMark: *srtBOM,
+		ShowRoles:        *showRoles,
+		MaxCueSeconds:    *maxCueSeconds,
+		SplitOnSentences: *splitOnSentences,
+		MaxLineLength:    *maxLineLength,
+		MarkdownAnchors:  *mdAnchors,
+		VTTNote:          *vttNote,
+		VTTVoiceClasses:  *vttVoiceClasses,
+		TextTimestamps:   *txtTimestamps,
+		MinCueGap:        *minCueGap,
 	}
 }
 
@@ -216,30 +788,202 @@ func getIntFlag(long, short int) int {
 	return short
 }
 
+// parseTimecode parses a time of the form HH:MM:SS, MM:SS, or SS into
+// seconds. The seconds may have a fractional part (e.g., "01:02:03.5").
+func parseTimecode(timecode string) (float64, error) {
+	parts := strings.Split(strings.TrimSpace(timecode), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("expected HH:MM:SS, got '%s'", timecode)
+	}
+	var seconds float64
+	for i, part := range parts {
+		var value float64
+		var err error
+		if i == len(parts)-1 {
+			value, err = strconv.ParseFloat(part, 64)
+		} else {
+			var n int
+			n, err = strconv.Atoi(part)
+			value = float64(n)
+		}
+		if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) || (i > 0 && value >= 60) {
+			return 0, fmt.Errorf("expected HH:MM:SS, got '%s'", timecode)
+		}
+		seconds = seconds*60 + value
+	}
+	return seconds, nil
+}
+
+// parseKeyValueList parses a comma-separated list of key=value pairs
+func parseKeyValueList(list string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if strings.TrimSpace(list) == "" {
+		return pairs, nil
+	}
+	for _, item := range strings.Split(list, ",") {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("expected name=value, got '%s'", strings.TrimSpace(item))
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOutputWritable verifies the output file's directory accepts new files
+// by creating and removing a temporary file in it
+func checkOutputWritable(output string) error {
+	if output == stdoutPath {
+		return nil
+	}
+	dir := filepath.Dir(output)
+	tmpFile, err := os.CreateTemp(dir, ".podcast-transcribe-*")
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("output directory %s is not writable: permission denied", dir)
+		}
+		if os.IsNotExist(err) {
+			return fmt.Errorf("output directory %s does not exist", dir)
+		}
+		return fmt.Errorf("cannot write to output directory %s: %w", dir, err)
+	}
+	tmpFile.Close()
+	return os.Remove(tmpFile.Name())
+}
+
+// validFormatList returns the supported formats as a comma-separated list
+func validFormatList() string {
+	names := make([]string, 0, len(formats.ValidFormats()))
+	for _, f := range formats.ValidFormats() {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
 // printUsage prints the usage information
 func printUsage() {
 	fmt.Fprintf(os.Stderr, `Usage: podcast-transcribe [flags] <audio-file-1> <audio-file-2> [audio-file-n...]
 
 Transcribe podcast audio files using Whisper. Each audio file should contain
-a single speaker's isolated track.
+a single speaker's isolated track. An audio file of "-" reads raw 16kHz mono
+float32 little-endian samples from standard input. A directory argument
+transcribes every supported audio file inside it, and a quoted glob pattern
+such as "tracks/*.wav" is expanded internally; speakers for those files are
+named after the file unless --speakers is given.
 
 Required Flags:
-  --output, -o    Output file path
-  --format, -f    Output format (txt, srt, vtt, json)
+  --output, -o    Output file path, or - to write to standard output (requires --format)
+  --output-dir    Instead of --output, write one file per speaker into this directory,
+                  created if needed
+  --format, -f    Output format (txt, srt, vtt, json, edl, ssml, csv, md, labels, ttml, html, ass, jsonl, ansi)
+                  May be omitted when the output extension names the format (also .htm,
+                  .markdown, .ndjson, and .dfxp)
 
 Optional Flags:
   --speakers, -s       Comma-separated list of speaker names (e.g., "Alice,Bob")
+  --speaker-roles      Speaker roles as name=role pairs (e.g., "Alice=Host,Bob=Guest")
+  --show-roles         Include speaker roles in text headings (e.g., "Alice — Host:")
   --model, -m          Whisper model: tiny, base, small, medium, large, large-v3 (default: large-v3)
-  --model-path         Path to Whisper model file (auto-detect if not provided)
+  --model-path         Path or http(s) URL of Whisper model file (auto-detect if not provided)
+                       URLs are downloaded once and cached in the model directory
+  --model-sha256       Expected SHA-256 checksum of a downloaded model
+  --download           Download the model into the model directory if it is missing,
+                       verified against --model-sha256, or else the checksum Hugging Face publishes
+                       with the file, which only shows the transfer completed
   --language, -l       Language code (e.g., "en", "es") or "auto" for detection (default: auto)
+  --prompt             Initial prompt for whisper, useful for proper nouns and domain jargon
+                       (e.g., "Kubernetes, Grafana, Prometheus")
+  --translate          Translate speech to English; output is English regardless of the
+                       source language (works with --language to force the source)
+  --normalize          Scale quiet tracks up to a 0.95 peak before transcription
+  --trim-silence       Skip leading and trailing silence in each track; timestamps are unchanged
+  --silence-threshold  RMS level (0-1) below which audio counts as silence (default: 0.01, about -40 dBFS)
+  --channel-mix        Reduce multichannel audio to mono by average, left, right, or channel number (default: average)
+  --beam-size          Beam search width; only 0 is supported, since the whisper Go binding always
+                       decodes greedily (default: 0)
+  --temperature        Sampling temperature (default: 0)
   --parallel, -p       Number of parallel transcription jobs (default: number of CPU cores)
-  --transcribers, -t   Number of transcriber instances (default: 1, each uses ~3GB memory)
+  --transcribers, -t   Number of transcriber instances (default: 1, each uses ~3GB memory for large models)
+  --allow-overcommit   Keep the --transcribers count even if it exceeds available memory
+  --load-concurrency   Number of transcriber instances to load at once (default: 1)
+  --retries            Retry a failed transcription up to N times with backoff (default: 0)
+  --file-timeout       Fail a file whose transcription takes longer than this duration, e.g. 30m (default: 0, no limit)
+  --cache-dir          Cache transcriptions in this directory and reuse them when a file's audio, the
+                       model, and the decoding settings are unchanged (default: no caching). Entries
+                       are kept until the directory is deleted
+  --cache              Cache transcriptions in podcast-tools/transcripts in the user cache directory
+  --chunk-seconds      Split each file into chunks of N seconds transcribed in parallel (default: 0, whole files)
+  --edl-min-silence    Minimum silence in seconds to cut in EDL output (default: 1.0)
+  --srt-words-per-cue  Split SRT cues every N words using word timestamps (default: 0, one cue per segment)
+  --srt-bom            Start SRT output with a UTF-8 byte order mark, for legacy players that
+                       need it to detect the encoding (default: no mark)
+  --max-cue-seconds    Split SRT/VTT/ASS cues longer than this many seconds (default: 0, no limit)
+  --split-on-sentences Prefer sentence ends (.?!) when splitting long cues
+  --max-line-length    Wrap SRT/VTT/ASS cue text at this many characters per line (default: 0, no wrapping)
+  --min-cue-gap        Shorten SRT/VTT cues so each ends this many seconds before the next starts
+  --vtt-note           Add a NOTE block with the duration and speakers to VTT output
+  --vtt-voice-classes  Add a class derived from the speaker name to VTT voice tags for styling
+                       (e.g., <v.speaker-1 Speaker 1>)
+  --txt-timestamps     Prefix each speaker turn in text output with its HH:MM:SS start time
+  --md-anchors         Render Markdown timestamps as linkable anchors
+  --output-template    File name of each speaker's file in --output-dir, where {speaker} is the
+                       sanitized speaker name and {format} the format (default: transcript.{speaker}.{format})
+  --source-ids         Include whisper source segment indices in JSON output
+  --suppress-bleed     Drop duplicate segments picked up by another speaker's microphone
+  --rename             Rename speakers after transcription (e.g., "Speaker 1=Alice,Speaker 2=Bob")
+  --dedupe             Collapse runs of identical consecutive segments from one speaker, which
+                       whisper produces when it loops on silence or music
+  --remove-fillers     Remove filler words (um, uh, er, ah, hmm, ...) from segment text
+  --merge-gap          Merge consecutive same-speaker segments separated by at most this many seconds
+  --max-segment-duration Split segments longer than this many seconds at word boundaries
+  --since              Keep only segments ending after this time in the recording (HH:MM:SS)
+  --until              Keep only segments starting before this time in the recording (HH:MM:SS)
+  --clip-window        Clip segments at the --since/--until edges to the window
+  --trim-silence-edges Shift timestamps so the transcript starts at the first spoken word
+  --trim-trailing-silence With --trim-silence-edges, also end the transcript at the last spoken word
+  --offset             Seconds to add to every timestamp, e.g. 5 for an added intro (negative to subtract)
+  --sort               Segment order: "time" for chronological (default) or "speaker" to group
+                       each speaker's segments together, in time order; not for subtitle formats
+  --allow-duplicates   Warn instead of failing on repeated audio files or speaker labels
+  --strict-audio       Fail if audio files differ in sample rate or channel count
+  --keep-going         Write a transcript of the files that succeeded when others fail
+                       (the exit status is still non-zero)
+  --config             JSON file of settings and audio files (see Config File below)
+  --manifest           File listing audio files, one "path[,speaker[,language]]" per line
+  --split-stereo       Transcribe the left and right channels of one stereo file as two
+                       speakers, named in order (e.g., "Alice,Bob")
+  --shard              Transcribe only shard i of N of the audio files (e.g., "2/4")
+  --merge-shards       Merge per-shard JSON transcripts given as arguments into one output
+  --warn-overlaps      Print segments from different speakers that overlap in time (crosstalk)
+  --stats              Print per-speaker segments, words, and speaking time after transcription
+  --benchmark          Print each file's real-time factor (audio seconds per wall-clock second),
+                       the overall factor, and the model load time after transcription
+  --check              Validate arguments, the model path, and that every audio file decodes,
+                       then exit without transcribing
+  --json-schema        Print the JSON Schema for json output and exit
+  --list-models        List standard and downloaded models in the model directory and exit
+  --probe              Load the model, print its size, whether it is multilingual, and every
+                       language it supports, then exit
   --verbose, -v        Enable verbose logging
 
 Examples:
   # Basic usage with two speakers
   podcast-transcribe -o transcript.txt -f txt host.wav guest.wav
 
+  # Format inferred from the output extension
+  podcast-transcribe -o transcript.srt host.wav guest.wav
+
   # With speaker labels and SRT output
   podcast-transcribe -o transcript.srt -f srt -s "Alice,Bob" alice.wav bob.wav
 
@@ -252,11 +996,65 @@ Examples:
   # Specify custom model path
   podcast-transcribe -o transcript.txt -f txt --model-path /path/to/model.bin audio.wav
 
+  # Split a large manifest across two machines, then merge the results
+  podcast-transcribe -o shard1.json -f json --manifest files.txt --shard 1/2   # machine 1
+  podcast-transcribe -o shard2.json -f json --manifest files.txt --shard 2/2   # machine 2
+  podcast-transcribe -o transcript.srt -f srt --merge-shards shard1.json shard2.json
+
+  # One SRT file per speaker for dubbing (subs/transcript.Alice.srt, ...)
+  podcast-transcribe --output-dir subs -f srt -s "Alice,Bob" alice.wav bob.wav
+
+  # Transcribe every track in a directory, naming speakers after the files
+  podcast-transcribe -o transcript.txt -f txt tracks/
+  podcast-transcribe -o transcript.txt -f txt "tracks/*.wav"
+
+  # Transcribe audio piped from ffmpeg without a temporary WAV file
+  ffmpeg -i episode.m4a -f f32le -ac 1 -ar 16000 - | podcast-transcribe -o transcript.txt -f txt -s Alice -
+
+  # Write JSON to standard output for another tool
+  podcast-transcribe -o - -f json -s "Alice,Bob" alice.wav bob.wav | jq '.segments | length'
+
+  # Use a model hosted on an internal server
+  podcast-transcribe -o transcript.txt -f txt --model-path https://models.example.com/ggml-custom.bin audio.wav
+
 Supported Formats:
-  txt   Plain text with speaker labels
-  srt   SubRip subtitle format with timestamps
-  vtt   WebVTT subtitle format with voice tags
-  json  Structured JSON with all metadata
+  txt     Plain text with speaker labels
+  srt     SubRip subtitle format with timestamps
+  vtt     WebVTT subtitle format with voice tags
+  json    Structured JSON with all metadata
+  edl     CMX 3600 edit decision list of spoken regions (cuts dead air)
+  ssml    SSML with a voice per speaker and pauses, for text-to-speech re-synthesis
+  csv     One row per segment: speaker, start_time, end_time, text
+  md      Markdown with bold speaker headings and [MM:SS] timestamps, for show notes
+  labels  Audacity label track (import via File > Import > Labels)
+  ttml    TTML timed text with a ttm:agent per speaker, for broadcast playout
+  html    Self-contained web page with colored speakers and data-start/data-end times
+  ass     Advanced SubStation Alpha subtitles with a colored style per speaker
+  jsonl   JSON Lines: one segment object per line, for log and analytics ingestion
+  ansi    Plain text with a color per speaker, for previewing with -o - (plain when piped)
+
+Config File:
+  --config reads a JSON file with any of these settings. Flags given on the
+  command line (long or short form) override the file, and audio files given
+  as arguments or with --manifest replace its "files" list.
+
+  {
+    "output": "transcript.srt",
+    "format": "srt",
+    "speakers": ["Alice", "Bob"],
+    "model": "large-v3",
+    "language": "auto",
+    "parallel": 2,
+    "transcribers": 2,
+    "files": [
+      {"path": "alice.wav", "speaker": "Alice"},
+      {"path": "bob.wav", "speaker": "Bob", "language": "es"}
+    ]
+  }
+
+Environment:
+  PODCAST_TOOLS_MODEL_DIR, WHISPER_MODEL_DIR
+                  Model directory (default: ~/.cache/whisper); the first one set is used
 
 `)
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"skriptble.dev/podcast-tools/transcriber"
+)
+
+// listModels prints which of the standard models are in the model directory,
+// with download URLs for the missing ones, followed by any other models found
+func listModels(w io.Writer) error {
+	files, err := transcriber.ListModels()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Model directory: %s\n\n", transcriber.GetDefaultModelDir())
+
+	fmt.Fprintln(w, "Standard models:")
+	for _, name := range transcriber.KnownModels {
+		i := slices.IndexFunc(files, func(f transcriber.ModelFile) bool { return f.Name == name })
+		if i < 0 {
+			fmt.Fprintf(w, "  %-10s missing  %s\n", name, transcriber.ModelDownloadURL(name))
+			continue
+		}
+		fmt.Fprintf(w, "  %-10s present  %d MB\n", name, files[i].Size>>20)
+	}
+
+	var others []transcriber.ModelFile
+	for _, f := range files {
+		if !slices.Contains(transcriber.KnownModels, f.Name) {
+			others = append(others, f)
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintln(w, "\nOther models:")
+		for _, f := range others {
+			fmt.Fprintf(w, "  %-10s present  %d MB\n", f.Name, f.Size>>20)
+		}
+	}
+	return nil
+}
+
+// resolveModelPath returns the local model file to load: modelPath if given,
+// otherwise the named standard model in the model directory. Models given by
+// URL are fetched into the cache, and a missing standard model is downloaded
+// when download is set, reporting the download on info. Downloads stop
+// cleanly on Ctrl-C. The error for a missing model says how to get it.
+func resolveModelPath(info io.Writer, modelName, modelPath, sha256 string, download bool, logger *slog.Logger) (string, error) {
+	path := modelPath
+	if path == "" {
+		path = transcriber.GetDefaultModelPath(modelName)
+		if path == "" {
+			return "", fmt.Errorf("could not determine home directory for model cache; set %s", transcriber.ModelDirEnv)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Download models given by URL into the cache
+	if transcriber.IsModelURL(path) {
+		return transcriber.FetchModelContext(ctx, path, sha256, logger)
+	}
+
+	// Download a missing standard model if asked to
+	if _, err := os.Stat(path); os.IsNotExist(err) && download && modelPath == "" {
+		fmt.Fprintf(info, "Downloading model %s to %s...\n", modelName, path)
+		if err := transcriber.DownloadModelContext(ctx, modelName, path, sha256, logger); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		var help strings.Builder
+		fmt.Fprintf(&help, "Whisper model not found at %s\n", path)
+		fmt.Fprintln(&help, "\nPlease download the model from:")
+		fmt.Fprintln(&help, "  https://huggingface.co/ggerganov/whisper.cpp/tree/main")
+		fmt.Fprintf(&help, "\nExpected model file: ggml-%s.bin\n", modelName)
+		fmt.Fprintf(&help, "Default location: %s", filepath.Dir(path))
+		if modelPath == "" {
+			fmt.Fprint(&help, "\n\nOr rerun with --download to download it automatically.")
+		}
+		return "", errors.New(help.String())
+	}
+	return path, nil
+}
+
+// probeModel loads the model at modelPath and prints its name, size, and the
+// languages it supports, wrapped to fit a terminal
+func probeModel(w io.Writer, modelPath string, logger *slog.Logger) error {
+	t, err := transcriber.NewWhisperTranscriber(transcriber.WhisperConfig{
+		ModelPath: modelPath,
+		Logger:    logger,
+	})
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	info := t.ModelInfo()
+
+	kind := "English-only"
+	if info.Multilingual {
+		kind = "multilingual"
+	}
+	fmt.Fprintf(w, "Model:     %s\n", info.Name)
+	fmt.Fprintf(w, "Path:      %s\n", info.Path)
+	fmt.Fprintf(w, "Size:      %d MB\n", info.Size>>20)
+	fmt.Fprintf(w, "Type:      %s\n", kind)
+	fmt.Fprintf(w, "Languages: %d\n", len(info.Languages))
+
+	const width = 72
+	line := " "
+	for i, lang := range info.Languages {
+		if i < len(info.Languages)-1 {
+			lang += ","
+		}
+		if len(line)+1+len(lang) > width {
+			fmt.Fprintln(w, line)
+			line = " "
+		}
+		line += " " + lang
+	}
+	fmt.Fprintln(w, line)
+	return nil
+}
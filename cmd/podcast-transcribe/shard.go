@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"skriptble.dev/podcast-tools/formats"
+	"skriptble.dev/podcast-tools/models"
+	"skriptble.dev/podcast-tools/transcriber"
+)
+
+// readManifest reads a list of audio files, one "path[,speaker[,language]]"
+// per line. Blank lines and lines starting with # are ignored. The returned
+// speakers and languages slices have an entry for every path, empty when no
+// value was given.
+func readManifest(path string) ([]string, []string, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	var paths, speakers, languages []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		audioPath, rest, _ := strings.Cut(line, ",")
+		speaker, language, _ := strings.Cut(rest, ",")
+		paths = append(paths, strings.TrimSpace(audioPath))
+		speakers = append(speakers, strings.TrimSpace(speaker))
+		languages = append(languages, strings.TrimSpace(language))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return paths, speakers, languages, nil
+}
+
+// parseShard parses a shard specification of the form "i/N"
+func parseShard(spec string) (int, int, error) {
+	indexStr, countStr, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected i/N, got '%s'", spec)
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(indexStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard index '%s'", indexStr)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid shard count '%s'", countStr)
+	}
+	return index, count, nil
+}
+
+// runMergeShards combines per-shard JSON transcripts into one time-sorted
+// transcript and writes it in the requested format, reporting progress on info
+func runMergeShards(info io.Writer, inputs []string, output string, format formats.Format, verbose bool) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("at least one shard JSON file is required")
+	}
+
+	shards := make([]*models.Transcript, 0, len(inputs))
+	for _, input := range inputs {
+		file, err := os.Open(input)
+		if err != nil {
+			return fmt.Errorf("failed to open shard: %w", err)
+		}
+		transcript, err := formats.ParseJSON(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		if verbose {
+			fmt.Fprintf(info, "Read %d segments from %s\n", len(transcript.Segments), input)
+		}
+		shards = append(shards, transcript)
+	}
+
+	transcript := models.Concat(shards...)
+	transcript.SortByTime()
+	transforms, err := transformsFromFlags()
+	if err != nil {
+		return err
+	}
+	if err := transcriber.ApplyTransforms(transcript, transforms); err != nil {
+		return err
+	}
+
+	if err := writeOutput(output, transcript, format); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(info, "Merged %d shard(s) into %s\n", len(inputs), outputName(output))
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"skriptble.dev/podcast-tools/formats"
+	"skriptble.dev/podcast-tools/models"
+)
+
+// defaultOutputTemplate names per-speaker output files, e.g.
+// transcript.Alice.srt
+const defaultOutputTemplate = "transcript.{speaker}.{format}"
+
+// checkOutputTemplate verifies that template names one file per speaker
+// inside the output directory
+func checkOutputTemplate(template string) error {
+	if !strings.Contains(template, "{speaker}") {
+		return fmt.Errorf("must contain {speaker} so each speaker gets its own file")
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return fmt.Errorf("must be a file name, not a path; use --output-dir for the directory")
+	}
+	return nil
+}
+
+// checkOutputDirWritable creates the output directory if needed and
+// verifies it accepts new files
+func checkOutputDirWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return checkOutputWritable(filepath.Join(dir, defaultOutputTemplate))
+}
+
+// speakerFileName expands the output template for one speaker. The speaker
+// label is sanitized since it comes from user input or audio file names.
+func speakerFileName(template, speaker string, format formats.Format) string {
+	return strings.NewReplacer(
+		"{speaker}", sanitizeFileName(speaker),
+		"{format}", string(format),
+	).Replace(template)
+}
+
+// sanitizeFileName makes a speaker label safe to use in a file name on any
+// platform by replacing whitespace, path separators, control characters,
+// and characters Windows reserves with underscores. Leading dots are
+// dropped so a label cannot produce a hidden file or "..".
+func sanitizeFileName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	safe = strings.TrimLeft(safe, ".")
+	if safe == "" {
+		return "unknown"
+	}
+	return safe
+}
+
+// writeSpeakerFiles writes each speaker's segments to its own file in dir,
+// named by expanding template, and returns the paths written in order of
+// first appearance. The directory is created if needed. Labels that
+// sanitize to the same file name are rejected rather than overwriting one
+// another.
+func writeSpeakerFiles(dir, template string, transcript *models.Transcript, format formats.Format) ([]string, error) {
+	speakers := transcript.SpeakerNames()
+	if len(speakers) == 0 {
+		return nil, fmt.Errorf("transcript has no segments to write")
+	}
+
+	paths := make([]string, len(speakers))
+	owners := make(map[string]string)
+	for i, speaker := range speakers {
+		name := speakerFileName(template, speaker, format)
+		if other, ok := owners[name]; ok {
+			return nil, fmt.Errorf("speakers '%s' and '%s' would both be written to %s; rename one with --rename", other, speaker, name)
+		}
+		owners[name] = speaker
+		paths[i] = filepath.Join(dir, name)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for i, speaker := range speakers {
+		if err := writeOutput(paths[i], transcript.FilterBySpeaker(speaker), format); err != nil {
+			return nil, fmt.Errorf("%s: %w", speaker, err)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"skriptble.dev/podcast-tools/models"
+	"skriptble.dev/podcast-tools/transcriber"
+)
+
+// printStats writes a table of each speaker's segments, words, and speaking
+// time, in order of first appearance, followed by the totals
+func printStats(w io.Writer, transcript *models.Transcript) {
+	stats := transcript.Stats()
+
+	var totalTime float64
+	for _, s := range stats {
+		totalTime += s.TotalSpeakingTime
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Speaker\tSegments\tWords\tSpeaking time\tShare\tAvg segment\t")
+	for _, name := range transcript.SpeakerNames() {
+		s := stats[name]
+		share := 0.0
+		if totalTime > 0 {
+			share = 100 * s.TotalSpeakingTime / totalTime
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%.1f%%\t%.1fs\t\n", name, s.SegmentCount, s.WordCount,
+			models.FormatTimestamp(s.TotalSpeakingTime), share, s.AverageSegmentLength)
+	}
+	fmt.Fprintf(tw, "Total\t%d\t%d\t%s\t\n", len(transcript.Segments), transcript.WordCount(),
+		models.FormatTimestamp(totalTime))
+	tw.Flush()
+}
+
+// printBenchmark writes a table of each file's audio length, transcription
+// time, and real-time factor, followed by the overall figures and the model
+// load time, for comparing models and hardware
+func printBenchmark(w io.Writer, timings transcriber.Timings, files []transcriber.AudioFile) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "File\tSpeaker\tAudio\tWall time\tRTF\t")
+	for i, result := range timings.Files {
+		name := filepath.Base(files[i].Path)
+		if result.Error != nil {
+			fmt.Fprintf(tw, "%s\t%s\t-\t%.1fs\tfailed\t\n", name, result.Speaker, result.WallTime.Seconds())
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.1fs\t%.1fs\t%.2fx\t\n", name, result.Speaker,
+			result.AudioSeconds, result.WallTime.Seconds(), result.RealTimeFactor())
+	}
+	fmt.Fprintf(tw, "Total\t\t%.1fs\t%.1fs\t%.2fx\t\n",
+		timings.AudioSeconds(), timings.Transcribe.Seconds(), timings.RealTimeFactor())
+	tw.Flush()
+	fmt.Fprintf(w, "Model load: %.2fs (slowest of %d instance(s))\n", timings.ModelLoad.Seconds(), timings.Transcribers)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestPrintStatsZeroSpeakingTime(t *testing.T) {
+	transcript := models.NewTranscript()
+	transcript.AddSegments([]models.Segment{
+		{Speaker: "Alice", Text: "hello", StartTime: 0, EndTime: 0},
+		{Speaker: "Bob", Text: "hi", StartTime: 0, EndTime: 0},
+	})
+
+	var sb strings.Builder
+	printStats(&sb, transcript)
+	out := sb.String()
+	if strings.Contains(out, "NaN") || strings.Contains(out, "Inf") {
+		t.Fatalf("printStats divided by zero:\n%s", out)
+	}
+	if got := strings.Count(out, "0.0%"); got != 2 {
+		t.Errorf("got %d speakers with a 0.0%% share, want 2:\n%s", got, out)
+	}
+}
@@ -0,0 +1,45 @@
+package formats
+
+import (
+	"hash/fnv"
+	"io"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// ansiReset ends an ANSI color sequence
+const ansiReset = "\x1b[0m"
+
+// ansiPalette holds the bold foreground colors speaker headings are drawn
+// from, skipping black and white so every color reads on light and dark
+// terminals
+var ansiPalette = []string{
+	"\x1b[1;31m", "\x1b[1;32m", "\x1b[1;33m", "\x1b[1;34m", "\x1b[1;35m", "\x1b[1;36m",
+	"\x1b[1;91m", "\x1b[1;92m", "\x1b[1;93m", "\x1b[1;94m", "\x1b[1;95m", "\x1b[1;96m",
+}
+
+// formatANSI formats a transcript as plain text with each speaker heading
+// colored for terminal preview
+func formatANSI(transcript *models.Transcript, opts Options) (string, error) {
+	var sb strings.Builder
+	if err := writeANSI(&sb, transcript, opts); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeANSI writes the same output as writeText, with each speaker heading
+// wrapped in that speaker's ANSI color
+func writeANSI(w io.Writer, transcript *models.Transcript, opts Options) error {
+	return writeTurns(w, transcript, opts, true)
+}
+
+// ansiColor returns the ANSI color for a speaker. Colors are picked by a
+// hash of the name rather than order of appearance, so a speaker keeps the
+// same color across runs and transcripts.
+func ansiColor(speaker string) string {
+	h := fnv.New32a()
+	h.Write([]byte(speaker))
+	return ansiPalette[h.Sum32()%uint32(len(ansiPalette))]
+}
@@ -0,0 +1,95 @@
+package formats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// assStyleFormat lists the fields of each style line, in order
+const assStyleFormat = "Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, " +
+	"Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, " +
+	"Alignment, MarginL, MarginR, MarginV, Encoding"
+
+// formatASS formats a transcript as Advanced SubStation Alpha (ASS) subtitles
+// Each speaker gets a style named after them with a color from the speaker
+// palette; styles can be restyled in Aegisub or any ASS editor afterwards.
+// ASS format:
+// [Script Info]
+// ScriptType: v4.00+
+//
+// [V4+ Styles]
+// Style: Speaker,Arial,48,&H00B4771F,...
+//
+// [Events]
+// Dialogue: 0,0:00:00.00,0:00:05.00,Speaker,Speaker,0,0,0,,Text
+func formatASS(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("[Script Info]\n")
+	sb.WriteString("Title: podcast-transcribe\n")
+	sb.WriteString("ScriptType: v4.00+\n")
+	sb.WriteString("WrapStyle: 0\n")
+	sb.WriteString("ScaledBorderAndShadow: yes\n")
+	sb.WriteString("PlayResX: 1920\n")
+	sb.WriteString("PlayResY: 1080\n\n")
+
+	// One style per speaker: bottom-centered text in the speaker's color
+	// with a black outline
+	sb.WriteString("[V4+ Styles]\n")
+	sb.WriteString("Format: " + assStyleFormat + "\n")
+	for i, speaker := range transcript.SpeakerNames() {
+		sb.WriteString(fmt.Sprintf("Style: %s,Arial,48,%s,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,2,1,2,20,20,40,1\n",
+			assField(speaker), assColor(speakerColor(i))))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("[Events]\n")
+	sb.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, cue := range buildCues(transcript, 0, opts) {
+		text := strings.ReplaceAll(wrapLines(assText(cue.Text), opts.MaxLineLength), "\n", `\N`)
+		sb.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,%s,0,0,0,,%s\n",
+			formatASSTimestamp(cue.StartTime), formatASSTimestamp(cue.EndTime),
+			assField(cue.Speaker), assField(cue.Speaker), text))
+	}
+
+	return sb.String(), nil
+}
+
+// formatASSTimestamp converts seconds to ASS timestamp format (H:MM:SS.cc)
+func formatASSTimestamp(seconds float64) string {
+	hours := int(seconds) / 3600
+	minutes := (int(seconds) % 3600) / 60
+	secs := int(seconds) % 60
+	centis := int((seconds - float64(int(seconds))) * 100)
+
+	return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, secs, centis)
+}
+
+// assColor converts a "#rrggbb" color to ASS's &HAABBGGRR form, fully opaque
+func assColor(hex string) string {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return "&H00FFFFFF"
+	}
+	r, g, b := rgb>>16&0xFF, rgb>>8&0xFF, rgb&0xFF
+	return fmt.Sprintf("&H00%02X%02X%02X", b, g, r)
+}
+
+// assField makes a speaker label safe for a comma-separated style or name field
+func assField(s string) string {
+	return strings.ReplaceAll(s, ",", ";")
+}
+
+// assText collapses whitespace in dialogue text and replaces braces, which
+// ASS would otherwise read as override tags
+func assText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer("{", "(", "}", ")").Replace(s)
+}
@@ -0,0 +1,29 @@
+package formats
+
+import (
+	"fmt"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatAudacityLabels formats a transcript as an Audacity label track
+// Each segment becomes one tab-separated label with times in seconds. Tabs
+// and newlines inside the text are collapsed so every label stays on one line.
+// Audacity label format:
+// 0.000	5.000	Speaker: Text
+func formatAudacityLabels(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	for _, segment := range transcript.Segments {
+		text := strings.Join(strings.Fields(segment.Text), " ")
+		sb.WriteString(fmt.Sprintf("%.3f\t%.3f\t%s: %s\n",
+			segment.StartTime, segment.EndTime, segment.Speaker, text))
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,51 @@
+package formats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatCSV formats a transcript as CSV with one row per segment
+// Fields containing commas, quotes, or newlines are quoted per RFC 4180.
+// The duration column is in seconds, so spreadsheets can sum it directly;
+// it is 0 for segments whose end is before their start.
+// CSV format:
+// speaker,start_time,end_time,duration,text
+// Speaker,00:00:00.000,00:00:05.000,5.000,Text
+func formatCSV(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+
+	// Header row
+	if err := w.Write([]string{"speaker", "start_time", "end_time", "duration", "text"}); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, segment := range transcript.Segments {
+		record := []string{
+			segment.Speaker,
+			formatVTTTimestamp(segment.StartTime),
+			formatVTTTimestamp(segment.EndTime),
+			strconv.FormatFloat(segment.Duration(), 'f', 3, 64),
+			strings.TrimSpace(segment.Text),
+		}
+		if err := w.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,27 @@
+package formats
+
+import (
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestCSVDuration(t *testing.T) {
+	transcript := transcriptOf(
+		models.Segment{Speaker: "Alice", Text: "Hello, welcome.", StartTime: 0, EndTime: 5},
+		models.Segment{Speaker: "Bob", Text: "Thanks!", StartTime: 5, EndTime: 8.5},
+		models.Segment{Speaker: "Bob", Text: "Reversed.", StartTime: 10, EndTime: 9},
+	)
+
+	out, err := FormatTranscript(transcript, FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "speaker,start_time,end_time,duration,text\n" +
+		"Alice,00:00:00.000,00:00:05.000,5.000,\"Hello, welcome.\"\n" +
+		"Bob,00:00:05.000,00:00:08.500,3.500,Thanks!\n" +
+		"Bob,00:00:10.000,00:00:09.000,0.000,Reversed.\n"
+	if out != want {
+		t.Errorf("CSV output:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,229 @@
+package formats
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// cue is a single subtitle entry before it is rendered
+type cue struct {
+	Speaker   string
+	Text      string
+	StartTime float64
+	EndTime   float64
+	Words     []models.Word // Word timings matching the words of Text, if known
+}
+
+// buildCues converts segments into subtitle cues. When wordsPerCue is
+// positive, segments with word timings are broken into cues of at most that
+// many words using the individual word times; other segments become a single
+// cue. Cues longer than opts.MaxCueSeconds are then split.
+func buildCues(transcript *models.Transcript, wordsPerCue int, opts Options) []cue {
+	cues := make([]cue, 0, len(transcript.Segments))
+
+	for _, segment := range transcript.Segments {
+		if wordsPerCue <= 0 || len(segment.Words) == 0 {
+			cues = append(cues, splitLongCue(cue{
+				Speaker:   segment.Speaker,
+				Text:      strings.TrimSpace(segment.Text),
+				StartTime: segment.StartTime,
+				EndTime:   segment.EndTime,
+				Words:     segment.Words,
+			}, opts.MaxCueSeconds, opts.SplitOnSentences)...)
+			continue
+		}
+
+		for start := 0; start < len(segment.Words); start += wordsPerCue {
+			end := start + wordsPerCue
+			if end > len(segment.Words) {
+				end = len(segment.Words)
+			}
+			words := segment.Words[start:end]
+
+			texts := make([]string, len(words))
+			for i, word := range words {
+				texts[i] = strings.TrimSpace(word.Text)
+			}
+
+			cues = append(cues, splitLongCue(cue{
+				Speaker:   segment.Speaker,
+				Text:      strings.Join(texts, " "),
+				StartTime: words[0].StartTime,
+				EndTime:   words[len(words)-1].EndTime,
+				Words:     words,
+			}, opts.MaxCueSeconds, opts.SplitOnSentences)...)
+		}
+	}
+
+	return cues
+}
+
+// splitLongCue divides a cue longer than maxSeconds into consecutive cues of
+// roughly equal length at word boundaries. With onSentences set, boundaries
+// after a sentence end (.?!) closest to the target length are preferred, and
+// plain word boundaries are used only when no sentence end fits. Split times
+// come from word timings when available, otherwise they are interpolated by
+// word count.
+func splitLongCue(c cue, maxSeconds float64, onSentences bool) []cue {
+	words := strings.Fields(c.Text)
+	duration := c.EndTime - c.StartTime
+	if maxSeconds <= 0 || duration <= maxSeconds || len(words) < 2 {
+		return []cue{c}
+	}
+
+	timed := len(c.Words) == len(words)
+
+	// startAt and endAt give the start of word k and the end of the first k words
+	startAt := func(k int) float64 {
+		if k == 0 {
+			return c.StartTime
+		}
+		if timed {
+			return c.Words[k].StartTime
+		}
+		return c.StartTime + duration*float64(k)/float64(len(words))
+	}
+	endAt := func(k int) float64 {
+		if k == len(words) {
+			return c.EndTime
+		}
+		if timed {
+			return c.Words[k-1].EndTime
+		}
+		return c.StartTime + duration*float64(k)/float64(len(words))
+	}
+	endsSentence := func(k int) bool {
+		return strings.ContainsAny(words[k-1][len(words[k-1])-1:], ".?!")
+	}
+
+	target := duration / math.Ceil(duration/maxSeconds)
+
+	var cues []cue
+	for start := 0; start < len(words); {
+		pieceStart := startAt(start)
+
+		// The remainder fits in one cue
+		end := len(words)
+		if c.EndTime-pieceStart > maxSeconds {
+			end = -1
+			if onSentences {
+				end = closestBoundary(start, len(words), pieceStart, target, maxSeconds, endAt, endsSentence)
+			}
+			if end < 0 {
+				end = closestBoundary(start, len(words), pieceStart, target, maxSeconds, endAt, nil)
+			}
+			if end < 0 {
+				end = len(words) // A single word longer than maxSeconds
+			}
+		}
+
+		piece := cue{
+			Speaker:   c.Speaker,
+			Text:      strings.Join(words[start:end], " "),
+			StartTime: pieceStart,
+			EndTime:   endAt(end),
+		}
+		if timed {
+			piece.Words = c.Words[start:end]
+		}
+		cues = append(cues, piece)
+		start = end
+	}
+
+	return cues
+}
+
+// closestBoundary returns the word boundary after start whose end time is
+// closest to pieceStart+target without the piece exceeding maxSeconds. Only
+// boundaries accepted by accept (if non-nil) are considered; -1 is returned
+// if none is. Without accept, the first boundary is always allowed so every
+// piece holds at least one word.
+func closestBoundary(start, numWords int, pieceStart, target, maxSeconds float64, endAt func(int) float64, accept func(int) bool) int {
+	ideal := pieceStart + target
+	best := -1
+	for k := start + 1; k < numWords; k++ {
+		t := endAt(k)
+		if t-pieceStart > maxSeconds && (accept != nil || k > start+1) {
+			break
+		}
+		if accept != nil && !accept(k) {
+			continue
+		}
+		if best < 0 || math.Abs(t-ideal) < math.Abs(endAt(best)-ideal) {
+			best = k
+		}
+	}
+	return best
+}
+
+// enforceCueGap ends each cue at least minGap seconds before the next cue
+// starts, for players that glitch when cues touch or overlap. Only cues that
+// end too late are changed, and a cue's end is never moved before its start.
+func enforceCueGap(cues []cue, minGap float64) {
+	if minGap <= 0 {
+		return
+	}
+	for i := 0; i+1 < len(cues); i++ {
+		latestEnd := cues[i+1].StartTime - minGap
+		if cues[i].EndTime > latestEnd {
+			cues[i].EndTime = max(latestEnd, cues[i].StartTime)
+		}
+	}
+}
+
+// wrapLines wraps text onto lines of at most maxLen characters, breaking at
+// word boundaries. Words longer than maxLen, including runs of CJK text
+// without spaces, are broken mid-word so that no line exceeds the limit.
+// Lengths are counted in runes, so multibyte characters are never split. A
+// maxLen of zero or less returns text unchanged.
+func wrapLines(text string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		// Break words that cannot fit on a line of their own
+		for utf8.RuneCountInString(word) > maxLen {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			n := wordBreak(runes, maxLen)
+			lines = append(lines, string(runes[:n]))
+			word = string(runes[n:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= maxLen:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// wordBreak returns where to break runes to fit a line of maxLen: at maxLen,
+// moved earlier if needed so a combining mark (e.g., the accent in a
+// decomposed "é") stays with the character it modifies
+func wordBreak(runes []rune, maxLen int) int {
+	n := maxLen
+	for n > 1 && unicode.Is(unicode.Mn, runes[n]) {
+		n--
+	}
+	return n
+}
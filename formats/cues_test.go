@@ -0,0 +1,65 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestWrapLinesMultibyte(t *testing.T) {
+	const decomposedE = "e\u0301" // e followed by a combining acute accent
+	tests := []struct {
+		name   string
+		text   string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "accented words wrap by characters, not bytes",
+			text:   "Ça va très bien, merci",
+			maxLen: 10,
+			want:   "Ça va très\nbien,\nmerci",
+		},
+		{
+			name:   "CJK run without spaces breaks between characters",
+			text:   "これは日本語のテキストです",
+			maxLen: 5,
+			want:   "これは日本\n語のテキス\nトです",
+		},
+		{
+			name:   "CJK fits on one line",
+			text:   "你好世界",
+			maxLen: 4,
+			want:   "你好世界",
+		},
+		{
+			name:   "combining accent stays with its letter",
+			text:   "caf" + decomposedE + "caf" + decomposedE,
+			maxLen: 4,
+			want:   "caf\n" + decomposedE + "ca\nf" + decomposedE,
+		},
+		{
+			name:   "no wrapping",
+			text:   "これは日本語のテキストです",
+			maxLen: 0,
+			want:   "これは日本語のテキストです",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapLines(tt.text, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("wrapLines(%q, %d) = %q, want %q", tt.text, tt.maxLen, got, tt.want)
+			}
+			for _, line := range strings.Split(got, "\n") {
+				if !utf8.ValidString(line) {
+					t.Errorf("line %q is not valid UTF-8", line)
+				}
+				if r, _ := utf8.DecodeRuneInString(line); unicode.Is(unicode.Mn, r) {
+					t.Errorf("line %q starts with a combining mark", line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package formats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+const (
+	// DefaultEDLMinSilence is the shortest silence (in seconds) cut from EDL output
+	DefaultEDLMinSilence = 1.0
+
+	// edlFrameRate is the timecode frame rate used for EDL events
+	edlFrameRate = 30
+)
+
+// formatEDL formats a transcript as a CMX 3600 style edit decision list
+// Each event keeps one spoken region; silences of at least opts.EDLMinSilence
+// seconds between regions are cut and noted as comments.
+// EDL format:
+// TITLE: podcast-transcribe
+// FCM: NON-DROP FRAME
+//
+// 001  AX       A     C        00:00:00:00 00:00:05:00 00:00:00:00 00:00:05:00
+// * CUT 00:00:05:00 00:00:12:15
+func formatEDL(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+	minSilence := opts.EDLMinSilence
+	if minSilence <= 0 {
+		minSilence = DefaultEDLMinSilence
+	}
+
+	var sb strings.Builder
+
+	// EDL header
+	sb.WriteString("TITLE: podcast-transcribe\n")
+	sb.WriteString("FCM: NON-DROP FRAME\n\n")
+
+	recordTime := 0.0
+	lastEnd := 0.0
+	for i, region := range speechRegions(transcript, minSilence) {
+		// Note the silence removed before this region
+		if region[0]-lastEnd >= minSilence {
+			sb.WriteString(fmt.Sprintf("* CUT %s %s\n",
+				formatEDLTimecode(lastEnd), formatEDLTimecode(region[0])))
+		}
+
+		// Source in/out is the original timeline, record in/out is the edited one
+		length := region[1] - region[0]
+		sb.WriteString(fmt.Sprintf("%03d  AX       A     C        %s %s %s %s\n",
+			i+1,
+			formatEDLTimecode(region[0]), formatEDLTimecode(region[1]),
+			formatEDLTimecode(recordTime), formatEDLTimecode(recordTime+length)))
+
+		recordTime += length
+		lastEnd = region[1]
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// speechRegions merges segment time ranges into spoken regions, joining any
+// regions separated by less than minSilence seconds
+func speechRegions(transcript *models.Transcript, minSilence float64) [][2]float64 {
+	segments := make([]models.Segment, len(transcript.Segments))
+	copy(segments, transcript.Segments)
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].StartTime < segments[j].StartTime
+	})
+
+	var regions [][2]float64
+	for _, segment := range segments {
+		// A segment whose end is before its start covers no time
+		start, end := segment.StartTime, max(segment.EndTime, segment.StartTime)
+		if n := len(regions); n > 0 && start-regions[n-1][1] < minSilence {
+			regions[n-1][1] = math.Max(regions[n-1][1], end)
+			continue
+		}
+		regions = append(regions, [2]float64{start, end})
+	}
+	return regions
+}
+
+// formatEDLTimecode converts seconds to EDL timecode format (HH:MM:SS:FF)
+func formatEDLTimecode(seconds float64) string {
+	totalFrames := int(math.Round(seconds * edlFrameRate))
+	frames := totalFrames % edlFrameRate
+	totalSeconds := totalFrames / edlFrameRate
+	hours := totalSeconds / 3600
+	minutes := (totalSeconds % 3600) / 60
+	secs := totalSeconds % 60
+
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", hours, minutes, secs, frames)
+}
@@ -1,7 +1,10 @@
 package formats
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 
 	"skriptble.dev/podcast-tools/models"
@@ -11,15 +14,44 @@ import (
 type Format string
 
 const (
-	FormatTXT  Format = "txt"
-	FormatSRT  Format = "srt"
-	FormatVTT  Format = "vtt"
-	FormatJSON Format = "json"
+	FormatTXT      Format = "txt"
+	FormatSRT      Format = "srt"
+	FormatVTT      Format = "vtt"
+	FormatJSON     Format = "json"
+	FormatEDL      Format = "edl"
+	FormatSSML     Format = "ssml"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "md"
+	FormatAudacity Format = "labels"
+	FormatTTML     Format = "ttml"
+	FormatHTML     Format = "html"
+	FormatASS      Format = "ass"
+	FormatJSONL    Format = "jsonl"
+	FormatANSI     Format = "ansi"
 )
 
+// Options holds optional settings for formatters. The zero value produces
+// each format's default output.
+type Options struct {
+	EDLMinSilence    float64 // Minimum silence in seconds to cut in EDL output (0 = DefaultEDLMinSilence)
+	IncludeSourceIDs bool    // Include whisper source segment indices in JSON output
+	SRTWordsPerCue   int     // Split SRT cues every N words using word timings (0 = one cue per segment)
+	SRTByteOrderMark bool    // Start SRT output with a UTF-8 byte order mark, which some legacy players need to detect the encoding
+	ShowRoles        bool    // Include speaker roles in text headings
+	MaxCueSeconds    float64 // Split SRT/VTT/ASS cues longer than this many seconds (0 = no limit)
+	SplitOnSentences bool    // Prefer sentence ends over word boundaries when splitting long cues
+	MaxLineLength    int     // Wrap SRT/VTT/ASS cue text at this many characters per line (0 = no wrapping)
+	MarkdownAnchors  bool    // Render Markdown timestamps as linkable anchors
+	VTTNote          bool    // Add a NOTE block with the duration and speakers to VTT output
+	VTTVoiceClasses  bool    // Add a class derived from the speaker label to VTT voice tags (e.g., <v.speaker-1 Speaker 1>)
+	TextTimestamps   bool    // Prefix each speaker turn in text output with its start time
+	MinCueGap        float64 // Minimum seconds between consecutive SRT/VTT cues, shortening cues that touch or overlap (0 = off)
+	Validate         bool    // Return an error listing invalid segments (see Transcript.Validate) instead of formatting them
+}
+
 // ValidFormats returns a list of all supported formats
 func ValidFormats() []Format {
-	return []Format{FormatTXT, FormatSRT, FormatVTT, FormatJSON}
+	return []Format{FormatTXT, FormatSRT, FormatVTT, FormatJSON, FormatEDL, FormatSSML, FormatCSV, FormatMarkdown, FormatAudacity, FormatTTML, FormatHTML, FormatASS, FormatJSONL, FormatANSI}
 }
 
 // IsValidFormat checks if a format string is valid
@@ -33,18 +65,149 @@ func IsValidFormat(format string) bool {
 	return false
 }
 
+// extensionAliases maps other common file extensions to the format they
+// hold; every format's own name is also recognized as an extension
+var extensionAliases = map[string]Format{
+	"htm":      FormatHTML,
+	"markdown": FormatMarkdown,
+	"ndjson":   FormatJSONL,
+	"dfxp":     FormatTTML,
+}
+
+// FormatFromExtension returns the format matching a file path's extension,
+// compared case-insensitively (e.g., "out.srt" is FormatSRT and "notes.MD"
+// is FormatMarkdown). The boolean is false if the path has no extension or
+// no format matches it.
+func FormatFromExtension(path string) (Format, bool) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if ext == "" {
+		return "", false
+	}
+	for _, f := range ValidFormats() {
+		if string(f) == ext {
+			return f, true
+		}
+	}
+	f, ok := extensionAliases[ext]
+	return f, ok
+}
+
 // FormatTranscript transcribes a transcript to the specified format
 func FormatTranscript(transcript *models.Transcript, format Format) (string, error) {
+	return FormatTranscriptWithOptions(transcript, format, Options{})
+}
+
+// FormatTranscriptWithOptions transcribes a transcript to the specified format
+// using the given formatter options
+func FormatTranscriptWithOptions(transcript *models.Transcript, format Format, opts Options) (string, error) {
+	if err := validate(transcript, opts); err != nil {
+		return "", err
+	}
 	switch format {
 	case FormatTXT:
-		return formatText(transcript)
+		return formatText(transcript, opts)
 	case FormatSRT:
-		return formatSRT(transcript)
+		return formatSRT(transcript, opts)
 	case FormatVTT:
-		return formatVTT(transcript)
+		return formatVTT(transcript, opts)
 	case FormatJSON:
-		return formatJSON(transcript)
+		return formatJSON(transcript, opts)
+	case FormatEDL:
+		return formatEDL(transcript, opts)
+	case FormatSSML:
+		return formatSSML(transcript, opts)
+	case FormatCSV:
+		return formatCSV(transcript, opts)
+	case FormatMarkdown:
+		return formatMarkdown(transcript, opts)
+	case FormatAudacity:
+		return formatAudacityLabels(transcript, opts)
+	case FormatTTML:
+		return formatTTML(transcript, opts)
+	case FormatHTML:
+		return formatHTML(transcript, opts)
+	case FormatASS:
+		return formatASS(transcript, opts)
+	case FormatJSONL:
+		return formatJSONL(transcript, opts)
+	case FormatANSI:
+		return formatANSI(transcript, opts)
 	default:
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
 }
+
+// FormatTranscriptTo writes a transcript in the specified format to w
+func FormatTranscriptTo(w io.Writer, transcript *models.Transcript, format Format) error {
+	return FormatTranscriptToWithOptions(w, transcript, format, Options{})
+}
+
+// FormatTranscriptToWithOptions writes a transcript in the specified format to
+// w using the given formatter options. Text, SRT, VTT, JSON, and JSON Lines
+// output is streamed a segment or cue at a time rather than built in memory
+// first, so w should be buffered; other formats are formatted in full and
+// then written.
+func FormatTranscriptToWithOptions(w io.Writer, transcript *models.Transcript, format Format, opts Options) error {
+	if err := validate(transcript, opts); err != nil {
+		return err
+	}
+	switch format {
+	case FormatTXT:
+		return writeText(w, transcript, opts)
+	case FormatSRT:
+		return writeSRT(w, transcript, opts)
+	case FormatVTT:
+		return writeVTT(w, transcript, opts)
+	case FormatJSON:
+		return writeJSON(w, transcript, opts)
+	case FormatJSONL:
+		return writeJSONL(w, transcript, opts)
+	case FormatANSI:
+		return writeANSI(w, transcript, opts)
+	}
+
+	output, err := FormatTranscriptWithOptions(transcript, format, opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, output)
+	return err
+}
+
+// validate returns the problems Transcript.Validate finds as one error when
+// opts.Validate is set. Nil and empty transcripts are left for each
+// formatter to reject.
+func validate(transcript *models.Transcript, opts Options) error {
+	if !opts.Validate || transcript == nil {
+		return nil
+	}
+	if errs := transcript.Validate(); len(errs) > 0 {
+		return fmt.Errorf("invalid transcript: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// speakerColors are the "#rrggbb" colors formats with styling assign to
+// speakers in order of first appearance
+var speakerColors = []string{
+	"#1f77b4", "#d62728", "#2ca02c", "#9467bd",
+	"#ff7f0e", "#8c564b", "#e377c2", "#17becf",
+}
+
+// speakerColor returns the color for the speaker at index i in order of first
+// appearance, repeating the palette when there are more speakers than colors
+func speakerColor(i int) string {
+	return speakerColors[i%len(speakerColors)]
+}
+
+// speakerHeading returns the speaker label, followed by the speaker's role
+// when roles are requested and one is known
+func speakerHeading(transcript *models.Transcript, speaker string, opts Options) string {
+	if !opts.ShowRoles {
+		return speaker
+	}
+	if info, ok := transcript.Speakers[speaker]; ok && info.Role != "" {
+		return fmt.Sprintf("%s — %s", speaker, info.Role)
+	}
+	return speaker
+}
@@ -0,0 +1,65 @@
+package formats
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatHTML formats a transcript as a self-contained HTML document
+// Each segment is a <div> with its start and end times in seconds as data
+// attributes for syncing with an audio player, and each speaker gets a
+// distinct color through a "speaker-N" class styled in the embedded CSS.
+// HTML format:
+// <div class="segment speaker-1" data-start="0.000" data-end="5.000">
+//
+//	<span class="speaker">Speaker</span> <span class="text">Text</span>
+//
+// </div>
+func formatHTML(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	// Number speakers in order of first appearance for their CSS classes
+	classes := make(map[string]string)
+	speakers := transcript.SpeakerNames()
+	for i, speaker := range speakers {
+		classes[speaker] = fmt.Sprintf("speaker-%d", i+1)
+	}
+
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString("<html>\n")
+	sb.WriteString("<head>\n")
+	sb.WriteString("<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>Transcript</title>\n")
+	sb.WriteString("<style>\n")
+	sb.WriteString("body { font-family: sans-serif; max-width: 48em; margin: 2em auto; line-height: 1.5; }\n")
+	sb.WriteString(".segment { margin: 0.5em 0; }\n")
+	sb.WriteString(".speaker { font-weight: bold; }\n")
+	for i, speaker := range speakers {
+		color := speakerColor(i)
+		sb.WriteString(fmt.Sprintf(".%s .speaker { color: %s; }\n", classes[speaker], color))
+	}
+	sb.WriteString("</style>\n")
+	sb.WriteString("</head>\n")
+	sb.WriteString("<body>\n")
+
+	for _, segment := range transcript.Segments {
+		sb.WriteString(fmt.Sprintf("<div class=\"segment %s\" data-start=\"%.3f\" data-end=\"%.3f\">\n",
+			classes[segment.Speaker], segment.StartTime, segment.EndTime))
+		sb.WriteString(fmt.Sprintf("  <span class=\"speaker\">%s</span> <span class=\"text\">%s</span>\n",
+			html.EscapeString(speakerHeading(transcript, segment.Speaker, opts)),
+			html.EscapeString(strings.TrimSpace(segment.Text))))
+		sb.WriteString("</div>\n")
+	}
+
+	sb.WriteString("</body>\n")
+	sb.WriteString("</html>")
+
+	return sb.String(), nil
+}
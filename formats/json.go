@@ -3,52 +3,207 @@ package formats
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"skriptble.dev/podcast-tools/models"
 )
 
+// JSONGenerator identifies this package as the producer of JSON output
+const JSONGenerator = "podcast-tools"
+
 // TranscriptJSON represents the JSON structure for export
 type TranscriptJSON struct {
+	Metadata MetadataJSON  `json:"metadata"`
+	Speakers []SpeakerJSON `json:"speakers,omitempty"`
 	Segments []SegmentJSON `json:"segments"`
 	Duration float64       `json:"duration"`
 }
 
+// MetadataJSON describes how a JSON transcript was produced, so consumers
+// can tell schema versions apart as the format evolves
+type MetadataJSON struct {
+	SchemaVersion string `json:"schema_version"`
+	Generator     string `json:"generator"`
+	GeneratedAt   string `json:"generated_at"` // RFC 3339
+	Model         string `json:"model,omitempty"`
+	Language      string `json:"language,omitempty"`
+}
+
+// SpeakerJSON represents speaker metadata in JSON format
+type SpeakerJSON struct {
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+}
+
 // SegmentJSON represents a single segment in JSON format
 type SegmentJSON struct {
-	Speaker   string  `json:"speaker"`
-	Text      string  `json:"text"`
-	StartTime float64 `json:"start_time"`
-	EndTime   float64 `json:"end_time"`
+	Speaker    string     `json:"speaker"`
+	Text       string     `json:"text"`
+	StartTime  float64    `json:"start_time"`
+	EndTime    float64    `json:"end_time"`
+	SourceIDs  []int      `json:"source_ids,omitempty"`
+	Words      []WordJSON `json:"words,omitempty"`
+	Confidence float64    `json:"confidence,omitempty"`
+	Source     string     `json:"source,omitempty"`
+}
+
+// WordJSON represents a single word timing in JSON format
+type WordJSON struct {
+	Text       string  `json:"text"`
+	StartTime  float64 `json:"start_time"`
+	EndTime    float64 `json:"end_time"`
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // formatJSON formats a transcript as JSON
-func formatJSON(transcript *models.Transcript) (string, error) {
+func formatJSON(transcript *models.Transcript, opts Options) (string, error) {
+	var sb strings.Builder
+	if err := writeJSON(&sb, transcript, opts); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeJSON writes a transcript as indented JSON to w. Segments are encoded
+// and written one at a time, producing the same document as marshaling a
+// TranscriptJSON with json.MarshalIndent.
+func writeJSON(w io.Writer, transcript *models.Transcript, opts Options) error {
 	if transcript == nil || len(transcript.Segments) == 0 {
-		return "", fmt.Errorf("transcript is empty")
+		return fmt.Errorf("transcript is empty")
+	}
+
+	// Include speaker metadata only when some is known
+	var jsonSpeakers []SpeakerJSON
+	if len(transcript.Speakers) > 0 {
+		for _, name := range transcript.SpeakerNames() {
+			jsonSpeakers = append(jsonSpeakers, SpeakerJSON{
+				Name: name,
+				Role: transcript.Speakers[name].Role,
+			})
+		}
+	}
+
+	var err error
+	write := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	// writeValue writes v indented to sit at the given nesting depth
+	writeValue := func(v any, depth int) {
+		data, marshalErr := json.MarshalIndent(v, strings.Repeat("  ", depth), "  ")
+		if marshalErr != nil && err == nil {
+			err = fmt.Errorf("failed to marshal JSON: %w", marshalErr)
+		}
+		write("%s", data)
+	}
+
+	write("{\n")
+	write("  \"metadata\": ")
+	writeValue(MetadataJSON{
+		SchemaVersion: JSONSchemaVersion,
+		Generator:     JSONGenerator,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		Model:         transcript.Model,
+		Language:      transcript.Language,
+	}, 1)
+	write(",\n")
+	if jsonSpeakers != nil {
+		write("  \"speakers\": ")
+		writeValue(jsonSpeakers, 1)
+		write(",\n")
 	}
 
-	// Convert model segments to JSON segments
-	jsonSegments := make([]SegmentJSON, len(transcript.Segments))
+	write("  \"segments\": [\n")
 	for i, segment := range transcript.Segments {
-		jsonSegments[i] = SegmentJSON{
-			Speaker:   segment.Speaker,
-			Text:      segment.Text,
-			StartTime: segment.StartTime,
-			EndTime:   segment.EndTime,
+		if i > 0 {
+			write(",\n")
 		}
+		write("    ")
+		writeValue(segmentToJSON(segment, opts), 2)
 	}
+	write("\n  ],\n")
 
-	// Create the JSON structure
-	transcriptJSON := TranscriptJSON{
-		Segments: jsonSegments,
-		Duration: transcript.Duration(),
+	write("  \"duration\": ")
+	writeValue(transcript.Duration(), 1)
+	write("\n}")
+
+	return err
+}
+
+// segmentToJSON converts a model segment to its JSON representation
+func segmentToJSON(segment models.Segment, opts Options) SegmentJSON {
+	jsonSegment := SegmentJSON{
+		Speaker:    segment.Speaker,
+		Text:       segment.Text,
+		StartTime:  segment.StartTime,
+		EndTime:    segment.EndTime,
+		Confidence: segment.Confidence,
+		Source:     segment.Source,
+	}
+	if opts.IncludeSourceIDs {
+		jsonSegment.SourceIDs = segment.SourceIDs
 	}
+	for _, word := range segment.Words {
+		jsonSegment.Words = append(jsonSegment.Words, WordJSON{
+			Text:       word.Text,
+			StartTime:  word.StartTime,
+			EndTime:    word.EndTime,
+			Confidence: word.Confidence,
+		})
+	}
+	return jsonSegment
+}
 
-	// Marshal to JSON with indentation
-	jsonData, err := json.MarshalIndent(transcriptJSON, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+// ParseJSON reads a transcript in the format written by the JSON formatter.
+// Files written before the metadata block was added are also accepted.
+// Segments must have a non-negative start time and an end time no earlier
+// than their start time.
+func ParseJSON(r io.Reader) (*models.Transcript, error) {
+	var transcriptJSON TranscriptJSON
+	if err := json.NewDecoder(r).Decode(&transcriptJSON); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	transcript := models.NewTranscript()
+	transcript.Model = transcriptJSON.Metadata.Model
+	transcript.Language = transcriptJSON.Metadata.Language
+	for i, segment := range transcriptJSON.Segments {
+		if segment.StartTime < 0 {
+			return nil, fmt.Errorf("segment %d: start_time %.3f is negative", i+1, segment.StartTime)
+		}
+		if segment.EndTime < segment.StartTime {
+			return nil, fmt.Errorf("segment %d: end_time %.3f is before start_time %.3f",
+				i+1, segment.EndTime, segment.StartTime)
+		}
+
+		var words []models.Word
+		for _, word := range segment.Words {
+			words = append(words, models.Word{
+				Text:       word.Text,
+				StartTime:  word.StartTime,
+				EndTime:    word.EndTime,
+				Confidence: word.Confidence,
+			})
+		}
+		transcript.AddSegment(models.Segment{
+			Speaker:    segment.Speaker,
+			Text:       segment.Text,
+			StartTime:  segment.StartTime,
+			EndTime:    segment.EndTime,
+			SourceIDs:  segment.SourceIDs,
+			Words:      words,
+			Confidence: segment.Confidence,
+			Source:     segment.Source,
+		})
+	}
+	for _, speaker := range transcriptJSON.Speakers {
+		if speaker.Role != "" {
+			transcript.SetSpeakerRole(speaker.Name, speaker.Role)
+		}
 	}
 
-	return string(jsonData), nil
+	return transcript, nil
 }
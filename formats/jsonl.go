@@ -0,0 +1,36 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatJSONL formats a transcript as JSON Lines, one segment per line
+func formatJSONL(transcript *models.Transcript, opts Options) (string, error) {
+	var sb strings.Builder
+	if err := writeJSONL(&sb, transcript, opts); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeJSONL writes each segment to w as a compact SegmentJSON object on its
+// own line, with no wrapping array. Newlines inside segment text are escaped
+// by the JSON encoder, so every segment is exactly one line.
+func writeJSONL(w io.Writer, transcript *models.Transcript, opts Options) error {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return fmt.Errorf("transcript is empty")
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, segment := range transcript.Segments {
+		if err := encoder.Encode(segmentToJSON(segment, opts)); err != nil {
+			return fmt.Errorf("failed to write JSON line: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package formats
+
+import (
+	"fmt"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatMarkdown formats a transcript as Markdown for show notes
+// Consecutive segments from one speaker are grouped under a bold speaker
+// heading, one paragraph per segment, each starting with its timestamp.
+// With opts.MarkdownAnchors set, each timestamp is also an anchor that
+// links to itself so readers can share links to a point in the episode.
+// Anchor ids are the start second, with a -2, -3, ... suffix when several
+// segments start in the same second so every id stays unique.
+// Markdown format:
+// **Speaker**
+//
+// [MM:SS] Text
+func formatMarkdown(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	currentSpeaker := ""
+	seenIDs := make(map[string]int)
+	for i, segment := range transcript.Segments {
+		// Add a heading when the speaker changes
+		if i == 0 || segment.Speaker != currentSpeaker {
+			sb.WriteString(fmt.Sprintf("**%s**\n\n", speakerHeading(transcript, segment.Speaker, opts)))
+			currentSpeaker = segment.Speaker
+		}
+
+		timestamp := formatMarkdownTimestamp(segment.StartTime)
+		if opts.MarkdownAnchors {
+			id := fmt.Sprintf("t%d", int(segment.StartTime))
+			seenIDs[id]++
+			if n := seenIDs[id]; n > 1 {
+				id = fmt.Sprintf("%s-%d", id, n)
+			}
+			timestamp = fmt.Sprintf("<a id=\"%s\"></a>[\\[%s\\]](#%s)", id, timestamp, id)
+		} else {
+			timestamp = fmt.Sprintf("[%s]", timestamp)
+		}
+
+		sb.WriteString(fmt.Sprintf("%s %s\n\n", timestamp, strings.Join(strings.Fields(segment.Text), " ")))
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// formatMarkdownTimestamp converts seconds to MM:SS, or H:MM:SS from one hour on
+func formatMarkdownTimestamp(seconds float64) string {
+	hours := int(seconds) / 3600
+	minutes := (int(seconds) % 3600) / 60
+	secs := int(seconds) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, secs)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, secs)
+}
@@ -0,0 +1,30 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestMarkdownAnchorsUnique(t *testing.T) {
+	transcript := transcriptOf(
+		models.Segment{Speaker: "Alice", Text: "Yes.", StartTime: 5.1, EndTime: 5.4},
+		models.Segment{Speaker: "Bob", Text: "No.", StartTime: 5.5, EndTime: 5.8},
+		models.Segment{Speaker: "Alice", Text: "Maybe.", StartTime: 5.9, EndTime: 6.5},
+		models.Segment{Speaker: "Bob", Text: "Fine.", StartTime: 6.5, EndTime: 7},
+	)
+
+	out, err := FormatTranscriptWithOptions(transcript, FormatMarkdown, Options{MarkdownAnchors: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"t5", "t5-2", "t5-3", "t6"} {
+		if want := `<a id="` + id + `"></a>`; strings.Count(out, want) != 1 {
+			t.Errorf("want exactly one %s in output:\n%s", want, out)
+		}
+		if want := "(#" + id + ")"; strings.Count(out, want) != 1 {
+			t.Errorf("want exactly one link %s in output:\n%s", want, out)
+		}
+	}
+}
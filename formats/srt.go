@@ -1,7 +1,11 @@
 package formats
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"skriptble.dev/podcast-tools/models"
@@ -12,34 +16,56 @@ import (
 // 1
 // 00:00:00,000 --> 00:00:05,000
 // [Speaker]: Text
-func formatSRT(transcript *models.Transcript) (string, error) {
-	if transcript == nil || len(transcript.Segments) == 0 {
-		return "", fmt.Errorf("transcript is empty")
-	}
-
+func formatSRT(transcript *models.Transcript, opts Options) (string, error) {
 	var sb strings.Builder
+	if err := writeSRT(&sb, transcript, opts); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
 
-	for i, segment := range transcript.Segments {
-		// Subtitle number (1-indexed)
-		sb.WriteString(fmt.Sprintf("%d\n", i+1))
-
-		// Timestamp range (SRT uses comma for milliseconds)
-		startTime := formatSRTTimestamp(segment.StartTime)
-		endTime := formatSRTTimestamp(segment.EndTime)
-		sb.WriteString(fmt.Sprintf("%s --> %s\n", startTime, endTime))
+// writeSRT writes a transcript as SRT to w, one cue at a time
+func writeSRT(w io.Writer, transcript *models.Transcript, opts Options) error {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return fmt.Errorf("transcript is empty")
+	}
 
-		// Text with speaker label
-		text := strings.TrimSpace(segment.Text)
-		sb.WriteString(fmt.Sprintf("[%s]: %s\n", segment.Speaker, text))
+	if opts.SRTByteOrderMark {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
 
+	cues := buildCues(transcript, opts.SRTWordsPerCue, opts)
+	enforceCueGap(cues, opts.MinCueGap)
+	for i, cue := range cues {
 		// Blank line between subtitles
-		sb.WriteString("\n")
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n\n"); err != nil {
+				return err
+			}
+		}
+
+		// Subtitle number (1-indexed) and timestamp range (SRT uses comma
+		// for milliseconds), then text with speaker label, wrapped to the
+		// maximum line length
+		startTime := formatSRTTimestamp(cue.StartTime)
+		endTime := formatSRTTimestamp(cue.EndTime)
+		text := fmt.Sprintf("[%s]: %s", cue.Speaker, cue.Text)
+		if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s", i+1, startTime, endTime, wrapLines(text, opts.MaxLineLength)); err != nil {
+			return err
+		}
 	}
 
-	return strings.TrimSpace(sb.String()), nil
+	return nil
 }
 
+// utf8BOM is the UTF-8 encoding of the byte order mark U+FEFF
+const utf8BOM = "\uFEFF"
+
 // formatSRTTimestamp converts seconds to SRT timestamp format (HH:MM:SS,mmm)
+// The hours field is at least two digits and grows as needed, so timelines
+// past 99 hours render in full (e.g., 100:00:00,000).
 func formatSRTTimestamp(seconds float64) string {
 	hours := int(seconds) / 3600
 	minutes := (int(seconds) % 3600) / 60
@@ -48,3 +74,124 @@ func formatSRTTimestamp(seconds float64) string {
 
 	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, secs, millis)
 }
+
+// srtSpeakerPattern matches the "[Speaker]: text" convention used by formatSRT
+var srtSpeakerPattern = regexp.MustCompile(`^\[([^\]]*)\]:\s*(.*)$`)
+
+// ParseSRT reads a transcript in the format written by the SRT formatter
+// Text lines of the form "[Speaker]: text" set the segment speaker; other
+// text leaves the speaker empty. Blocks without a valid timestamp line are
+// skipped rather than treated as errors.
+func ParseSRT(r io.Reader) (*models.Transcript, error) {
+	transcript := models.NewTranscript()
+
+	var block []string
+	flush := func() {
+		if segment, ok := parseSRTBlock(block); ok {
+			transcript.AddSegment(segment)
+		}
+		block = block[:0]
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Files written with a byte order mark start with one
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), utf8BOM))
+		if line == "" {
+			flush()
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SRT: %w", err)
+	}
+	flush()
+
+	return transcript, nil
+}
+
+// parseSRTBlock converts the lines of one subtitle block into a segment
+// The cue number line is optional; lines after the timestamp are joined
+// into the segment text.
+func parseSRTBlock(lines []string) (models.Segment, bool) {
+	for i, line := range lines {
+		start, end, ok := parseSRTTimeRange(line)
+		if !ok {
+			continue
+		}
+
+		text := strings.Join(lines[i+1:], " ")
+		if text == "" {
+			return models.Segment{}, false
+		}
+
+		segment := models.Segment{
+			Text:      text,
+			StartTime: start,
+			EndTime:   end,
+		}
+		if m := srtSpeakerPattern.FindStringSubmatch(text); m != nil {
+			segment.Speaker = m[1]
+			segment.Text = m[2]
+		}
+		return segment, true
+	}
+
+	return models.Segment{}, false
+}
+
+// parseSRTTimeRange parses an "HH:MM:SS,mmm --> HH:MM:SS,mmm" line
+func parseSRTTimeRange(line string) (start, end float64, ok bool) {
+	from, to, found := strings.Cut(line, "-->")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := parseSRTTimestamp(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, false
+	}
+	// Ignore any cue settings after the end timestamp
+	fields := strings.Fields(to)
+	if len(fields) == 0 {
+		return 0, 0, false
+	}
+	end, err = parseSRTTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
+
+// parseSRTTimestamp converts an SRT timestamp (HH:MM:SS,mmm) to seconds
+// Hours may have more than two digits.
+// A period is also accepted as the millisecond separator.
+func parseSRTTimestamp(timestamp string) (float64, error) {
+	clock, millis, found := strings.Cut(strings.Replace(timestamp, ".", ",", 1), ",")
+	if !found {
+		return 0, fmt.Errorf("invalid SRT timestamp: %q", timestamp)
+	}
+
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid SRT timestamp: %q", timestamp)
+	}
+
+	var seconds float64
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid SRT timestamp: %q", timestamp)
+		}
+		seconds = seconds*60 + float64(n)
+	}
+
+	ms, err := strconv.Atoi(millis)
+	if err != nil || ms < 0 || len(millis) != 3 {
+		return 0, fmt.Errorf("invalid SRT timestamp: %q", timestamp)
+	}
+
+	return seconds + float64(ms)/1000, nil
+}
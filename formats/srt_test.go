@@ -0,0 +1,67 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// transcriptOf returns a transcript holding segments
+func transcriptOf(segments ...models.Segment) *models.Transcript {
+	transcript := models.NewTranscript()
+	transcript.AddSegments(segments)
+	return transcript
+}
+
+func TestSRTTimestampPast99Hours(t *testing.T) {
+	transcript := transcriptOf(models.Segment{Speaker: "Alice", Text: "Still here.", StartTime: 360000, EndTime: 360005.5})
+
+	out, err := FormatTranscript(transcript, FormatSRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "100:00:00,000 --> 100:00:05,500"; !strings.Contains(out, want) {
+		t.Errorf("SRT output missing %q:\n%s", want, out)
+	}
+
+	parsed, err := ParseSRT(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Segments) != 1 || parsed.Segments[0].StartTime != 360000 || parsed.Segments[0].EndTime != 360005.5 {
+		t.Errorf("ParseSRT round trip = %+v, want one segment from 360000 to 360005.5", parsed.Segments)
+	}
+}
+
+func TestSRTByteOrderMark(t *testing.T) {
+	segments := []models.Segment{
+		{Speaker: "Amélie", Text: "Ça va très bien.", StartTime: 0, EndTime: 2},
+		{Speaker: "健太", Text: "これは日本語です。", StartTime: 2, EndTime: 4},
+	}
+	for _, bom := range []bool{false, true} {
+		out, err := FormatTranscriptWithOptions(transcriptOf(segments...), FormatSRT, Options{SRTByteOrderMark: bom})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.HasPrefix(out, utf8BOM); got != bom {
+			t.Errorf("SRTByteOrderMark=%v: output starts with BOM = %v", bom, got)
+		}
+		if strings.Count(out, utf8BOM) > 1 {
+			t.Errorf("SRTByteOrderMark=%v: BOM written more than once", bom)
+		}
+
+		parsed, err := ParseSRT(strings.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parsed.Segments) != len(segments) {
+			t.Fatalf("SRTByteOrderMark=%v: ParseSRT returned %d segments, want %d", bom, len(parsed.Segments), len(segments))
+		}
+		for i, seg := range parsed.Segments {
+			if seg.Speaker != segments[i].Speaker || seg.Text != segments[i].Text {
+				t.Errorf("SRTByteOrderMark=%v: segment %d = %q: %q, want %q: %q", bom, i, seg.Speaker, seg.Text, segments[i].Speaker, segments[i].Text)
+			}
+		}
+	}
+}
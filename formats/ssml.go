@@ -0,0 +1,104 @@
+package formats
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+const (
+	// ssmlMinBreak is the shortest gap (in seconds) rendered as a <break>
+	ssmlMinBreak = 0.05
+
+	// ssmlMaxBreak is the longest <break> emitted; most TTS engines reject longer pauses
+	ssmlMaxBreak = 10.0
+)
+
+// formatSSML formats a transcript as SSML for text-to-speech re-synthesis
+// Consecutive segments from one speaker share a <voice> element named after
+// the speaker, and gaps between segments become <break> elements.
+// SSML format:
+// <speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis">
+//
+//	<voice name="Speaker">
+//	  Text
+//	  <break time="500ms"/>
+//	</voice>
+//
+// </speak>
+func formatSSML(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	// SSML header
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis">` + "\n")
+
+	// Segments without a speaker are read in the default voice, outside any
+	// voice element
+	currentSpeaker := ""
+	voiceOpen := false
+	for i, segment := range transcript.Segments {
+		// Pause for the silence since the previous segment
+		if i > 0 {
+			gap := segment.StartTime - transcript.Segments[i-1].EndTime
+			if gap >= ssmlMinBreak {
+				indent := "  "
+				if voiceOpen && segment.Speaker == currentSpeaker {
+					indent = "    "
+				} else if voiceOpen {
+					sb.WriteString("  </voice>\n")
+					voiceOpen = false
+					currentSpeaker = ""
+				}
+				sb.WriteString(fmt.Sprintf("%s<break time=\"%dms\"/>\n", indent, ssmlBreakMillis(gap)))
+			}
+		}
+
+		// Start a new voice when the speaker changes
+		if segment.Speaker != currentSpeaker {
+			if voiceOpen {
+				sb.WriteString("  </voice>\n")
+				voiceOpen = false
+			}
+			if segment.Speaker != "" {
+				sb.WriteString(fmt.Sprintf("  <voice name=\"%s\">\n", escapeXML(segment.Speaker)))
+				voiceOpen = true
+			}
+			currentSpeaker = segment.Speaker
+		}
+
+		indent := "  "
+		if voiceOpen {
+			indent = "    "
+		}
+		sb.WriteString(fmt.Sprintf("%s%s\n", indent, escapeXML(strings.TrimSpace(segment.Text))))
+	}
+	if voiceOpen {
+		sb.WriteString("  </voice>\n")
+	}
+	sb.WriteString("</speak>")
+
+	return sb.String(), nil
+}
+
+// ssmlBreakMillis converts a gap in seconds to a break length in milliseconds,
+// capped at ssmlMaxBreak
+func ssmlBreakMillis(gap float64) int {
+	if gap > ssmlMaxBreak {
+		gap = ssmlMaxBreak
+	}
+	return int(gap * 1000)
+}
+
+// escapeXML escapes text for use in XML content or attribute values
+func escapeXML(text string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
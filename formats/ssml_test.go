@@ -0,0 +1,57 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestSSMLVoicesBalanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []models.Segment
+		voices   int
+	}{
+		{
+			name: "empty first speaker",
+			segments: []models.Segment{
+				{Text: "Welcome.", StartTime: 0, EndTime: 1},
+				{Speaker: "Alice", Text: "Hi.", StartTime: 1, EndTime: 2},
+			},
+			voices: 1,
+		},
+		{
+			name: "empty speaker after a break",
+			segments: []models.Segment{
+				{Speaker: "Alice", Text: "Hi.", StartTime: 0, EndTime: 1},
+				{Text: "Music.", StartTime: 3, EndTime: 4},
+				{Text: "More music.", StartTime: 6, EndTime: 7},
+			},
+			voices: 1,
+		},
+		{
+			name: "no speakers",
+			segments: []models.Segment{
+				{Text: "Hello.", StartTime: 0, EndTime: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := FormatTranscript(transcriptOf(tt.segments...), FormatSSML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opened := strings.Count(out, "<voice ")
+			closed := strings.Count(out, "</voice>")
+			if opened != tt.voices || closed != tt.voices {
+				t.Errorf("got %d opening and %d closing voice tags, want %d of each:\n%s", opened, closed, tt.voices, out)
+			}
+			if strings.Contains(out, `<voice name="">`) {
+				t.Errorf("voice element opened for an empty speaker:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package formats
+
+import (
+	"fmt"
+	"strings"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// formatTTML formats a transcript as TTML (Timed Text Markup Language)
+// Each speaker is declared once as a ttm:agent in the head, and each segment
+// becomes a <p> referencing its speaker's agent.
+// TTML format:
+// <tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttm="http://www.w3.org/ns/ttml#metadata" xml:lang="">
+//
+//	<head>
+//	  <metadata>
+//	    <ttm:agent xml:id="speaker1" type="person">
+//	      <ttm:name type="full">Speaker</ttm:name>
+//	    </ttm:agent>
+//	  </metadata>
+//	</head>
+//	<body>
+//	  <div>
+//	    <p begin="00:00:00.000" end="00:00:05.000" ttm:agent="speaker1">Text</p>
+//	  </div>
+//	</body>
+//
+// </tt>
+func formatTTML(transcript *models.Transcript, opts Options) (string, error) {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return "", fmt.Errorf("transcript is empty")
+	}
+
+	var sb strings.Builder
+
+	// TTML header; an empty xml:lang marks the language as undetermined
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<tt xmlns="http://www.w3.org/ns/ttml" xmlns:ttm="http://www.w3.org/ns/ttml#metadata" xml:lang="">` + "\n")
+
+	// Declare an agent per speaker. Speaker labels are not valid XML IDs in
+	// general, so agents are numbered in order of first appearance.
+	agents := make(map[string]string)
+	sb.WriteString("  <head>\n")
+	sb.WriteString("    <metadata>\n")
+	for i, speaker := range transcript.SpeakerNames() {
+		agents[speaker] = fmt.Sprintf("speaker%d", i+1)
+		sb.WriteString(fmt.Sprintf("      <ttm:agent xml:id=\"%s\" type=\"person\">\n", agents[speaker]))
+		sb.WriteString(fmt.Sprintf("        <ttm:name type=\"full\">%s</ttm:name>\n",
+			escapeXML(speakerHeading(transcript, speaker, opts))))
+		sb.WriteString("      </ttm:agent>\n")
+	}
+	sb.WriteString("    </metadata>\n")
+	sb.WriteString("  </head>\n")
+
+	sb.WriteString("  <body>\n")
+	sb.WriteString("    <div>\n")
+	for _, segment := range transcript.Segments {
+		sb.WriteString(fmt.Sprintf("      <p begin=\"%s\" end=\"%s\" ttm:agent=\"%s\">%s</p>\n",
+			formatVTTTimestamp(segment.StartTime),
+			formatVTTTimestamp(segment.EndTime),
+			agents[segment.Speaker],
+			escapeXML(strings.TrimSpace(segment.Text))))
+	}
+	sb.WriteString("    </div>\n")
+	sb.WriteString("  </body>\n")
+	sb.WriteString("</tt>")
+
+	return sb.String(), nil
+}
@@ -2,34 +2,70 @@ package formats
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"skriptble.dev/podcast-tools/models"
 )
 
 // formatText formats a transcript as plain text
-func formatText(transcript *models.Transcript) (string, error) {
-	if transcript == nil || len(transcript.Segments) == 0 {
-		return "", fmt.Errorf("transcript is empty")
+// Segment text is joined with single spaces, collapsing any whitespace
+// whisper leaves around or inside segments.
+func formatText(transcript *models.Transcript, opts Options) (string, error) {
+	var sb strings.Builder
+	if err := writeText(&sb, transcript, opts); err != nil {
+		return "", err
 	}
+	return sb.String(), nil
+}
 
-	var sb strings.Builder
+// writeText writes a transcript as plain text to w, one speaker turn at a time
+func writeText(w io.Writer, transcript *models.Transcript, opts Options) error {
+	return writeTurns(w, transcript, opts, false)
+}
+
+// writeTurns writes the text output shared by the txt and ansi formats,
+// coloring each speaker heading if color is set
+func writeTurns(w io.Writer, transcript *models.Transcript, opts Options, color bool) error {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return fmt.Errorf("transcript is empty")
+	}
 
 	currentSpeaker := ""
+	var words []string
 	for _, segment := range transcript.Segments {
 		// Add speaker label when speaker changes
 		if segment.Speaker != currentSpeaker {
+			separator := ""
 			if currentSpeaker != "" {
-				sb.WriteString("\n") // Add blank line between speakers
+				// Finish the previous turn with a blank line between speakers
+				separator = strings.Join(words, " ") + "\n\n"
+				words = words[:0]
+			}
+			heading := speakerHeading(transcript, segment.Speaker, opts)
+			if opts.TextTimestamps {
+				heading = fmt.Sprintf("[%s] %s", turnTimestamp(segment.StartTime), heading)
+			}
+			if color {
+				heading = ansiColor(segment.Speaker) + heading + ansiReset
+			}
+			if _, err := fmt.Fprintf(w, "%s%s:\n", separator, heading); err != nil {
+				return err
 			}
-			sb.WriteString(fmt.Sprintf("%s:\n", segment.Speaker))
 			currentSpeaker = segment.Speaker
 		}
 
-		// Write the text
-		sb.WriteString(strings.TrimSpace(segment.Text))
-		sb.WriteString(" ")
+		// Collect the words of the text
+		words = append(words, strings.Fields(segment.Text)...)
 	}
 
-	return strings.TrimSpace(sb.String()), nil
+	_, err := io.WriteString(w, strings.Join(words, " "))
+	return err
+}
+
+// turnTimestamp formats the start of a speaker turn as HH:MM:SS, dropping the
+// milliseconds that FormatTimestamp includes
+func turnTimestamp(seconds float64) string {
+	timestamp, _, _ := strings.Cut(models.FormatTimestamp(seconds), ".")
+	return timestamp
 }
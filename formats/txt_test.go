@@ -0,0 +1,53 @@
+package formats
+
+import (
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestFormatTextWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []models.Segment
+		want     string
+	}{
+		{
+			name: "leading and trailing spaces",
+			segments: []models.Segment{
+				{Speaker: "Alice", Text: " hello"},
+				{Speaker: "Alice", Text: "world "},
+			},
+			want: "Alice:\nhello world",
+		},
+		{
+			name: "runs of whitespace inside and between segments",
+			segments: []models.Segment{
+				{Speaker: "Alice", Text: "  hello   there \t"},
+				{Speaker: "Alice", Text: "\n world  "},
+			},
+			want: "Alice:\nhello there world",
+		},
+		{
+			name: "speaker breaks keep their blank line",
+			segments: []models.Segment{
+				{Speaker: "Alice", Text: " hello"},
+				{Speaker: "Alice", Text: "world "},
+				{Speaker: "Bob", Text: "  hi  "},
+				{Speaker: "Alice", Text: " bye "},
+			},
+			want: "Alice:\nhello world\n\nBob:\nhi\n\nAlice:\nbye",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatTranscript(transcriptOf(tt.segments...), FormatTXT)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
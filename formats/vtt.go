@@ -2,45 +2,129 @@ package formats
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"unicode"
 
 	"skriptble.dev/podcast-tools/models"
 )
 
 // formatVTT formats a transcript as WebVTT subtitle format
+// Cues are numbered from 1. With opts.VTTNote set, a NOTE block with the
+// duration and speakers follows the header. With opts.VTTVoiceClasses set,
+// each voice tag also carries a class derived from the speaker label (e.g.,
+// <v.speaker-1 Speaker 1>).
 // VTT format:
 // WEBVTT
 //
+// 1
 // 00:00:00.000 --> 00:00:05.000
 // <v Speaker>Text
-func formatVTT(transcript *models.Transcript) (string, error) {
-	if transcript == nil || len(transcript.Segments) == 0 {
-		return "", fmt.Errorf("transcript is empty")
+func formatVTT(transcript *models.Transcript, opts Options) (string, error) {
+	var sb strings.Builder
+	if err := writeVTT(&sb, transcript, opts); err != nil {
+		return "", err
 	}
+	return sb.String(), nil
+}
 
-	var sb strings.Builder
+// writeVTT writes a transcript as WebVTT to w, one cue at a time
+func writeVTT(w io.Writer, transcript *models.Transcript, opts Options) error {
+	if transcript == nil || len(transcript.Segments) == 0 {
+		return fmt.Errorf("transcript is empty")
+	}
 
 	// VTT header
-	sb.WriteString("WEBVTT\n\n")
+	if _, err := io.WriteString(w, "WEBVTT"); err != nil {
+		return err
+	}
 
-	for _, segment := range transcript.Segments {
-		// Timestamp range (VTT uses period for milliseconds)
-		startTime := formatVTTTimestamp(segment.StartTime)
-		endTime := formatVTTTimestamp(segment.EndTime)
-		sb.WriteString(fmt.Sprintf("%s --> %s\n", startTime, endTime))
+	// Generator metadata, ignored by players
+	if opts.VTTNote {
+		if _, err := io.WriteString(w, vttNote(transcript)); err != nil {
+			return err
+		}
+	}
 
-		// Text with voice tag for speaker
-		text := strings.TrimSpace(segment.Text)
-		sb.WriteString(fmt.Sprintf("<v %s>%s\n", segment.Speaker, text))
+	cues := buildCues(transcript, 0, opts)
+	enforceCueGap(cues, opts.MinCueGap)
+	for i, cue := range cues {
+		// Blank line and cue identifier before each cue, then the timestamp
+		// range (VTT uses period for milliseconds)
+		startTime := formatVTTTimestamp(cue.StartTime)
+		endTime := formatVTTTimestamp(cue.EndTime)
 
-		// Blank line between cues
-		sb.WriteString("\n")
+		// Text with voice tag for speaker; the tag is not displayed so it
+		// does not count toward the line length
+		text := wrapLines(cue.Text, opts.MaxLineLength)
+		if _, err := fmt.Fprintf(w, "\n\n%d\n%s --> %s\n%s%s", i+1, startTime, endTime, vttVoiceTag(cue.Speaker, opts), text); err != nil {
+			return err
+		}
 	}
 
-	return strings.TrimSpace(sb.String()), nil
+	return nil
+}
+
+// vttVoiceEscaper escapes the characters a voice tag's annotation may not
+// contain
+var vttVoiceEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// vttVoiceTag returns the voice tag opening a cue from speaker. The speaker
+// label is kept as the voice name, so "::cue(v[voice=...])" selectors match
+// it as written, and with opts.VTTVoiceClasses a class from vttVoiceClass is
+// added for "::cue(.class)" selectors.
+func vttVoiceTag(speaker string, opts Options) string {
+	voice := vttVoiceEscaper.Replace(speaker)
+	if opts.VTTVoiceClasses {
+		return fmt.Sprintf("<v.%s %s>", vttVoiceClass(speaker), voice)
+	}
+	return fmt.Sprintf("<v %s>", voice)
+}
+
+// vttVoiceClass derives a class name usable in both a WebVTT cue tag and a
+// CSS selector from a speaker label: letters are lowercased, digits kept,
+// and each run of other characters becomes a single hyphen, so "Speaker 1"
+// is "speaker-1". Labels that would start with a digit are prefixed with
+// "speaker-", and labels with no letters or digits become "speaker".
+// Distinct labels can share a class, such as "Dr. Lee" and "Dr Lee".
+func vttVoiceClass(speaker string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(speaker) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+
+	class := sb.String()
+	if class == "" {
+		return "speaker"
+	}
+	if unicode.IsDigit([]rune(class)[0]) {
+		return "speaker-" + class
+	}
+	return class
+}
+
+// vttNote returns a NOTE block, preceded by a blank line, describing the
+// transcript's duration and speakers. A NOTE block may not contain "-->", so
+// any in speaker labels are broken up.
+func vttNote(transcript *models.Transcript) string {
+	speakers := strings.Join(transcript.SpeakerNames(), ", ")
+	speakers = strings.ReplaceAll(speakers, "-->", "- ->")
+	return fmt.Sprintf("\n\nNOTE\nGenerated by podcast-transcribe\nDuration: %s\nSpeakers: %s",
+		formatVTTTimestamp(transcript.Duration()), speakers)
 }
 
 // formatVTTTimestamp converts seconds to VTT timestamp format (HH:MM:SS.mmm)
+// The hours field is at least two digits and grows as needed, so timelines
+// past 99 hours render in full (e.g., 100:00:00.000).
 func formatVTTTimestamp(seconds float64) string {
 	hours := int(seconds) / 3600
 	minutes := (int(seconds) % 3600) / 60
@@ -0,0 +1,20 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+func TestVTTTimestampPast99Hours(t *testing.T) {
+	transcript := transcriptOf(models.Segment{Speaker: "Alice", Text: "Still here.", StartTime: 360000, EndTime: 360005.5})
+
+	out, err := FormatTranscript(transcript, FormatVTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "100:00:00.000 --> 100:00:05.500"; !strings.Contains(out, want) {
+		t.Errorf("VTT output missing %q:\n%s", want, out)
+	}
+}
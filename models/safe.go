@@ -0,0 +1,41 @@
+package models
+
+import "sync"
+
+// SafeTranscript collects segments from multiple goroutines. Use Transcript
+// directly when a single goroutine builds the transcript; use SafeTranscript
+// when several goroutines append segments concurrently, then call Snapshot
+// to get a Transcript to sort, transform, and format. The zero value is an
+// empty transcript ready to use.
+type SafeTranscript struct {
+	mu         sync.Mutex
+	transcript Transcript
+}
+
+// NewSafeTranscript creates a new empty concurrent-safe transcript
+func NewSafeTranscript() *SafeTranscript {
+	return &SafeTranscript{}
+}
+
+// AddSegment adds a segment to the transcript
+func (s *SafeTranscript) AddSegment(segment Segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transcript.AddSegment(segment)
+}
+
+// AddSegments adds multiple segments to the transcript. The segments are
+// appended together, without segments from other goroutines between them.
+func (s *SafeTranscript) AddSegments(segments []Segment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transcript.AddSegments(segments)
+}
+
+// Snapshot returns a copy of the segments added so far. The copy shares no
+// memory with the SafeTranscript, so it is unaffected by later appends.
+func (s *SafeTranscript) Snapshot() *Transcript {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.Clone()
+}
@@ -2,21 +2,103 @@ package models
 
 import (
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Segment represents a single transcribed segment with speaker information and timing
 type Segment struct {
-	Speaker   string    // Speaker name or label
-	Text      string    // Transcribed text
-	StartTime float64   // Start time in seconds
-	EndTime   float64   // End time in seconds
+	Speaker    string  // Speaker name or label
+	Text       string  // Transcribed text
+	StartTime  float64 // Start time in seconds
+	EndTime    float64 // End time in seconds
+	SourceIDs  []int   // Indices of the whisper segments this segment was built from
+	Words      []Word  // Word-level timings, if available
+	Confidence float64 // Mean token probability in [0, 1], or 0 if unknown
+	Source     string  // Path of the audio file the segment was transcribed from, if known
 }
 
-// Transcript represents a complete transcript with multiple segments
+// Word represents a single word within a segment with its own timing
+type Word struct {
+	Text       string  // Word text without surrounding whitespace
+	StartTime  float64 // Start time in seconds
+	EndTime    float64 // End time in seconds
+	Confidence float64 // Mean token probability in [0, 1], or 0 if unknown
+}
+
+// Duration returns the length of the segment in seconds. Reversed times are
+// clamped rather than passed on: a segment whose end time precedes its start
+// time has zero length, so it never subtracts from totals such as speaking
+// time. Validate reports such segments as errors.
+func (s Segment) Duration() float64 {
+	if s.EndTime < s.StartTime {
+		return 0
+	}
+	return s.EndTime - s.StartTime
+}
+
+// Validate returns an error describing the first problem with the segment:
+// a start or end time that is negative or not a finite number, an end time
+// before the start time, or text that is empty or only whitespace
+func (s Segment) Validate() error {
+	if err := validateTime("start", s.StartTime); err != nil {
+		return err
+	}
+	if err := validateTime("end", s.EndTime); err != nil {
+		return err
+	}
+	if s.EndTime < s.StartTime {
+		return fmt.Errorf("end time %g is before start time %g", s.EndTime, s.StartTime)
+	}
+	if strings.TrimSpace(s.Text) == "" {
+		return fmt.Errorf("text is empty")
+	}
+	return nil
+}
+
+// validateTime checks that a timestamp is a finite, non-negative number
+func validateTime(name string, seconds float64) error {
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return fmt.Errorf("%s time %v is not a finite number", name, seconds)
+	}
+	if seconds < 0 {
+		return fmt.Errorf("%s time %g is negative", name, seconds)
+	}
+	return nil
+}
+
+// SpeakerStats summarizes one speaker's share of a transcript
+type SpeakerStats struct {
+	SegmentCount         int
+	WordCount            int
+	TotalSpeakingTime    float64 // Sum of segment durations in seconds
+	AverageSegmentLength float64 // Mean segment duration in seconds
+}
+
+// SpeakerInfo holds optional metadata about a speaker
+type SpeakerInfo struct {
+	Role string // Role or title, e.g. "Host" or "Guest"
+}
+
+// Transcript represents a complete transcript with multiple segments.
+// It is not safe for concurrent use; see SafeTranscript.
 type Transcript struct {
 	Segments []Segment
+	Speakers map[string]SpeakerInfo // Optional speaker metadata keyed by speaker label
+
+	// DetectedLanguages holds the language whisper transcribed each speaker
+	// in (e.g., "en"), keyed by speaker label. It is detected for speakers
+	// whose language was "auto" and is nil when unknown.
+	DetectedLanguages map[string]string
+
+	Model    string // Name of the whisper model that produced the transcript, if known
+	Language string // Language of the transcript text (e.g., "en"), if known
 }
 
 // NewTranscript creates a new empty transcript
@@ -31,11 +113,414 @@ func (t *Transcript) AddSegment(segment Segment) {
 	t.Segments = append(t.Segments, segment)
 }
 
+// SetSpeakerRole records the role for a speaker label
+func (t *Transcript) SetSpeakerRole(speaker, role string) {
+	if t.Speakers == nil {
+		t.Speakers = make(map[string]SpeakerInfo)
+	}
+	info := t.Speakers[speaker]
+	info.Role = role
+	t.Speakers[speaker] = info
+}
+
+// SetDetectedLanguage records the language a speaker was transcribed in
+func (t *Transcript) SetDetectedLanguage(speaker, language string) {
+	if t.DetectedLanguages == nil {
+		t.DetectedLanguages = make(map[string]string)
+	}
+	t.DetectedLanguages[speaker] = language
+}
+
+// SpeakerNames returns the distinct speaker labels in order of first appearance
+func (t *Transcript) SpeakerNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, seg := range t.Segments {
+		if !seen[seg.Speaker] {
+			seen[seg.Speaker] = true
+			names = append(names, seg.Speaker)
+		}
+	}
+	return names
+}
+
+// RenameSpeaker relabels every segment from speaker oldName as newName,
+// carrying over the speaker's metadata and detected language, and returns
+// the number of segments changed
+func (t *Transcript) RenameSpeaker(oldName, newName string) int {
+	return t.RenameSpeakers(map[string]string{oldName: newName})
+}
+
+// RenameSpeakers relabels speakers using names, which maps old labels to new
+// ones, and returns the number of segments changed. All renames apply at
+// once, so two labels can be swapped. Renaming a speaker to a label already
+// in use combines the two speakers. The combined speaker keeps the metadata
+// and detected language of the speaker that already had the label, or else
+// of the renamed speaker whose old label sorts first.
+func (t *Transcript) RenameSpeakers(names map[string]string) int {
+	changed := 0
+	for i := range t.Segments {
+		seg := &t.Segments[i]
+		if newName, ok := names[seg.Speaker]; ok && newName != seg.Speaker {
+			seg.Speaker = newName
+			changed++
+		}
+	}
+	t.Speakers = renameKeys(t.Speakers, names)
+	t.DetectedLanguages = renameKeys(t.DetectedLanguages, names)
+	return changed
+}
+
+// renameKeys returns a copy of m with its speaker keys renamed using names.
+// When several keys are renamed to one label, the value of the key that
+// already had the label wins, then the value of the first key in sorted
+// order, so the result does not depend on map iteration order.
+func renameKeys[V any](m map[string]V, names map[string]string) map[string]V {
+	if m == nil {
+		return nil
+	}
+	speakers := make([]string, 0, len(m))
+	for speaker := range m {
+		speakers = append(speakers, speaker)
+	}
+	sort.Strings(speakers)
+
+	result := make(map[string]V, len(m))
+	for _, speaker := range speakers {
+		if newName, ok := names[speaker]; !ok || newName == speaker {
+			result[speaker] = m[speaker]
+		}
+	}
+	for _, speaker := range speakers {
+		newName, ok := names[speaker]
+		if !ok || newName == speaker {
+			continue
+		}
+		if _, taken := result[newName]; !taken {
+			result[newName] = m[speaker]
+		}
+	}
+	return result
+}
+
 // AddSegments adds multiple segments to the transcript
 func (t *Transcript) AddSegments(segments []Segment) {
 	t.Segments = append(t.Segments, segments...)
 }
 
+// Concat combines transcripts into a new transcript without adjusting any
+// timestamps. Speaker metadata, detected languages, and the model and
+// language are merged, with later transcripts taking precedence. Call
+// SortByTime on the result for chronological order.
+func Concat(transcripts ...*Transcript) *Transcript {
+	result := NewTranscript()
+	for _, t := range transcripts {
+		if t == nil {
+			continue
+		}
+		result.AddSegments(t.Segments)
+		for speaker, info := range t.Speakers {
+			if result.Speakers == nil {
+				result.Speakers = make(map[string]SpeakerInfo)
+			}
+			result.Speakers[speaker] = info
+		}
+		for speaker, language := range t.DetectedLanguages {
+			result.SetDetectedLanguage(speaker, language)
+		}
+		if t.Model != "" {
+			result.Model = t.Model
+		}
+		if t.Language != "" {
+			result.Language = t.Language
+		}
+	}
+	return result
+}
+
+// Merge combines the transcripts of consecutive parts of a recording (e.g.,
+// Part 1 and Part 2 of an episode) into one sorted transcript. Each part is
+// shifted to start where the previous part ends, as measured by Duration,
+// plus the matching entry in gaps. gaps must be empty (parts follow each
+// other directly) or have one entry per boundary, len(transcripts)-1, with no
+// negative entries. Metadata is merged as in Concat, and the inputs are not
+// modified.
+func Merge(transcripts []*Transcript, gaps []float64) (*Transcript, error) {
+	if len(gaps) > 0 && len(gaps) != len(transcripts)-1 {
+		return nil, fmt.Errorf("expected %d gap(s) between %d transcripts, got %d",
+			max(len(transcripts)-1, 0), len(transcripts), len(gaps))
+	}
+	for i, gap := range gaps {
+		if gap < 0 {
+			return nil, fmt.Errorf("gap %d is negative: %g", i+1, gap)
+		}
+	}
+
+	parts := make([]*Transcript, 0, len(transcripts))
+	offset := 0.0
+	for i, t := range transcripts {
+		if i > 0 && len(gaps) > 0 {
+			offset += gaps[i-1]
+		}
+		if t == nil {
+			continue
+		}
+		part := t.Clone()
+		part.Shift(offset)
+		parts = append(parts, part)
+		offset += t.Duration()
+	}
+
+	result := Concat(parts...)
+	result.SortByTime()
+	return result, nil
+}
+
+// Clone returns a deep copy of the transcript, including each segment's word
+// timings and source IDs, so changes to the copy never affect the receiver
+func (t *Transcript) Clone() *Transcript {
+	result := &Transcript{
+		Segments:          make([]Segment, len(t.Segments)),
+		Speakers:          maps.Clone(t.Speakers),
+		DetectedLanguages: maps.Clone(t.DetectedLanguages),
+		Model:             t.Model,
+		Language:          t.Language,
+	}
+	for i, seg := range t.Segments {
+		seg.SourceIDs = slices.Clone(seg.SourceIDs)
+		seg.Words = slices.Clone(seg.Words)
+		result.Segments[i] = seg
+	}
+	return result
+}
+
+// FilterBySpeaker returns a new transcript containing only the segments from
+// speaker, in their original order. The receiver is not modified.
+func (t *Transcript) FilterBySpeaker(speaker string) *Transcript {
+	return t.filterSpeakers(func(s string) bool { return s == speaker })
+}
+
+// FilterBySpeakerFold is like FilterBySpeaker but compares speaker labels
+// case-insensitively, for labels whose capitalization differs between runs
+func (t *Transcript) FilterBySpeakerFold(speaker string) *Transcript {
+	return t.filterSpeakers(func(s string) bool { return strings.EqualFold(s, speaker) })
+}
+
+// filterSpeakers copies the segments, speaker metadata, and detected
+// languages for speakers that match into a new transcript
+func (t *Transcript) filterSpeakers(match func(speaker string) bool) *Transcript {
+	result := NewTranscript()
+	result.Model = t.Model
+	result.Language = t.Language
+	for _, seg := range t.Segments {
+		if !match(seg.Speaker) {
+			continue
+		}
+		seg.SourceIDs = slices.Clone(seg.SourceIDs)
+		seg.Words = slices.Clone(seg.Words)
+		result.AddSegment(seg)
+	}
+	for speaker, info := range t.Speakers {
+		if match(speaker) {
+			if result.Speakers == nil {
+				result.Speakers = make(map[string]SpeakerInfo)
+			}
+			result.Speakers[speaker] = info
+		}
+	}
+	for speaker, language := range t.DetectedLanguages {
+		if match(speaker) {
+			result.SetDetectedLanguage(speaker, language)
+		}
+	}
+	return result
+}
+
+// Window returns a new transcript containing the segments that overlap the
+// time range from start to end in seconds, in their original order, with
+// their timestamps unchanged. Speaker metadata, detected languages, and the
+// model and language are copied. The receiver is not modified.
+func (t *Transcript) Window(start, end float64) *Transcript {
+	return t.window(start, end, false)
+}
+
+// WindowClipped is like Window but clips segments that extend past either
+// edge of the window to its bounds, dropping the word timings that fall
+// outside it. Segment text is kept whole, since it cannot be divided
+// reliably without word timings.
+func (t *Transcript) WindowClipped(start, end float64) *Transcript {
+	return t.window(start, end, true)
+}
+
+// window implements Window and WindowClipped. A segment overlaps the window
+// if it starts before end and ends after start; zero-length segments count
+// when they fall inside it.
+func (t *Transcript) window(start, end float64, clip bool) *Transcript {
+	result := t.Clone()
+	segments := result.Segments[:0]
+	for _, seg := range result.Segments {
+		if seg.StartTime >= end || (seg.EndTime <= start && seg.StartTime < start) {
+			continue
+		}
+		if clip {
+			seg.StartTime = max(seg.StartTime, start)
+			seg.EndTime = min(seg.EndTime, end)
+			words := seg.Words[:0]
+			for _, w := range seg.Words {
+				if w.StartTime >= end || (w.EndTime <= start && w.StartTime < start) {
+					continue
+				}
+				w.StartTime = max(w.StartTime, start)
+				w.EndTime = min(w.EndTime, end)
+				words = append(words, w)
+			}
+			seg.Words = words
+		}
+		segments = append(segments, seg)
+	}
+	result.Segments = segments
+	return result
+}
+
+// LowConfidenceSegments returns the segments whose confidence is below
+// threshold, in transcript order. Segments with unknown confidence (0) are
+// not included.
+func (t *Transcript) LowConfidenceSegments(threshold float64) []Segment {
+	var result []Segment
+	for _, seg := range t.Segments {
+		if seg.Confidence > 0 && seg.Confidence < threshold {
+			result = append(result, seg)
+		}
+	}
+	return result
+}
+
+// Search returns the segments whose text contains query, in transcript order
+func (t *Transcript) Search(query string, caseInsensitive bool) []Segment {
+	return t.SearchWithContext(query, caseInsensitive, 0)
+}
+
+// SearchWithContext is like Search but also returns up to window segments
+// before and after each match, for the surrounding dialogue. Segments are
+// returned once each in transcript order, even when the context of nearby
+// matches overlaps. An empty query matches nothing.
+func (t *Transcript) SearchWithContext(query string, caseInsensitive bool, window int) []Segment {
+	if query == "" {
+		return nil
+	}
+	if window < 0 {
+		window = 0
+	}
+	if caseInsensitive {
+		query = strings.ToLower(query)
+	}
+
+	var result []Segment
+	next := 0 // First segment index not yet added to result
+	for i, seg := range t.Segments {
+		text := seg.Text
+		if caseInsensitive {
+			text = strings.ToLower(text)
+		}
+		if !strings.Contains(text, query) {
+			continue
+		}
+
+		start := max(i-window, next)
+		end := min(i+window+1, len(t.Segments))
+		result = append(result, t.Segments[start:end]...)
+		next = end
+	}
+	return result
+}
+
+// Validate checks every segment with Segment.Validate and returns one error
+// per invalid segment, naming its index, in transcript order. It returns nil
+// if every segment is valid. Nothing calls it implicitly, so transcripts built
+// by hand can be checked before formatting.
+func (t *Transcript) Validate() []error {
+	var errs []error
+	for i, seg := range t.Segments {
+		if err := seg.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("segment %d: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// Overlaps returns the index pairs of segments from different speakers whose
+// time ranges intersect, such as crosstalk between isolated tracks. Each
+// pair has the lower index first, and pairs are ordered by first index, then
+// second. Segments that merely touch (one ends as the other starts) do not
+// overlap.
+func (t *Transcript) Overlaps() [][2]int {
+	// Sweep segments in start order so each one is only compared with
+	// segments that start before it ends
+	order := make([]int, len(t.Segments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return t.Segments[order[a]].StartTime < t.Segments[order[b]].StartTime
+	})
+
+	var pairs [][2]int
+	for a, i := range order {
+		seg := t.Segments[i]
+		for _, j := range order[a+1:] {
+			other := t.Segments[j]
+			if other.StartTime >= seg.EndTime {
+				break
+			}
+			if other.Speaker == seg.Speaker || other.EndTime <= seg.StartTime {
+				continue
+			}
+			pairs = append(pairs, [2]int{min(i, j), max(i, j)})
+		}
+	}
+
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+	return pairs
+}
+
+// Gap is a stretch of the timeline during which no speaker is talking
+type Gap struct {
+	Start    float64 // Start time in seconds
+	End      float64 // End time in seconds
+	Duration float64 // Length in seconds
+}
+
+// Gaps returns the silences longer than minSeconds across all speakers, in
+// time order. The timeline starts at zero, so silence before the first
+// segment is included; it ends with the last segment, so nothing follows it.
+// Segments need not be sorted.
+func (t *Transcript) Gaps(minSeconds float64) []Gap {
+	segments := slices.Clone(t.Segments)
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].StartTime < segments[j].StartTime
+	})
+
+	// Walk the union of segment intervals, noting the holes between them
+	var gaps []Gap
+	covered := 0.0 // End of the speech seen so far
+	for _, seg := range segments {
+		if seg.StartTime-covered > minSeconds {
+			gaps = append(gaps, Gap{
+				Start:    covered,
+				End:      seg.StartTime,
+				Duration: seg.StartTime - covered,
+			})
+		}
+		covered = max(covered, seg.EndTime)
+	}
+	return gaps
+}
+
 // SortByTime sorts all segments chronologically by start time
 func (t *Transcript) SortByTime() {
 	sort.Slice(t.Segments, func(i, j int) bool {
@@ -43,7 +528,29 @@ func (t *Transcript) SortByTime() {
 	})
 }
 
-// Duration returns the total duration of the transcript in seconds
+// SortBySpeaker groups segments by speaker, with speakers in order of first
+// appearance, keeping each speaker's segments in time order. It is meant for
+// reading formats such as text and JSON; subtitle formats expect
+// chronological cues, so use SortByTime for those.
+func (t *Transcript) SortBySpeaker() {
+	rank := make(map[string]int)
+	for i, speaker := range t.SpeakerNames() {
+		rank[speaker] = i
+	}
+	sort.SliceStable(t.Segments, func(i, j int) bool {
+		a, b := t.Segments[i], t.Segments[j]
+		if rank[a.Speaker] != rank[b.Speaker] {
+			return rank[a.Speaker] < rank[b.Speaker]
+		}
+		return a.StartTime < b.StartTime
+	})
+}
+
+// Duration returns the total duration of the transcript in seconds, measured
+// from zero to the latest segment end time. It is 0 for an empty transcript or
+// one whose segments all end at time 0, so callers computing ratios against
+// it must check for zero first. Use Span for the time range the segments
+// actually cover.
 func (t *Transcript) Duration() float64 {
 	if len(t.Segments) == 0 {
 		return 0
@@ -58,6 +565,363 @@ func (t *Transcript) Duration() float64 {
 	return maxEndTime
 }
 
+// WordCount returns the number of whitespace-separated words in all segments
+func (t *Transcript) WordCount() int {
+	count := 0
+	for _, seg := range t.Segments {
+		count += len(strings.Fields(seg.Text))
+	}
+	return count
+}
+
+// Stats returns segment counts, word counts, and speaking time for each
+// speaker, keyed by speaker label. Words are counted by splitting segment
+// text on whitespace, and overlapping segments each count in full toward
+// their speaker's speaking time.
+func (t *Transcript) Stats() map[string]SpeakerStats {
+	stats := make(map[string]SpeakerStats)
+	for _, seg := range t.Segments {
+		s := stats[seg.Speaker]
+		s.SegmentCount++
+		s.WordCount += len(strings.Fields(seg.Text))
+		s.TotalSpeakingTime += seg.Duration()
+		stats[seg.Speaker] = s
+	}
+	for speaker, s := range stats {
+		s.AverageSegmentLength = s.TotalSpeakingTime / float64(s.SegmentCount)
+		stats[speaker] = s
+	}
+	return stats
+}
+
+// Span returns the earliest segment start and latest segment end in seconds,
+// the stretch of the timeline the transcript actually covers. Unlike
+// Duration it does not assume the timeline starts at zero, so a transcript
+// offset to begin at 30 seconds has a span starting at 30. Both are 0 for an
+// empty transcript.
+func (t *Transcript) Span() (start, end float64) {
+	if len(t.Segments) == 0 {
+		return 0, 0
+	}
+
+	start, end = t.Segments[0].StartTime, t.Segments[0].EndTime
+	for _, seg := range t.Segments[1:] {
+		start = min(start, seg.StartTime)
+		end = max(end, seg.EndTime)
+	}
+	return start, end
+}
+
+// TrimSilenceEdges rebases all timestamps so the transcript starts at the
+// first spoken segment and returns the number of seconds removed.
+func (t *Transcript) TrimSilenceEdges() float64 {
+	firstStart, _ := t.Span()
+	if firstStart <= 0 {
+		return 0
+	}
+
+	t.Shift(-firstStart)
+	return firstStart
+}
+
+// TrimTrailingSilence ends the transcript at the last spoken word by pulling
+// in segment end times that run past it, and returns the number of seconds
+// removed from Duration. Whisper often pads the final segment with the
+// silence that follows it; word timings show where speech actually stops, so
+// a segment without word timings counts as speech up to its end.
+func (t *Transcript) TrimTrailingSilence() float64 {
+	_, end := t.Span()
+	lastSpoken := 0.0
+	for _, seg := range t.Segments {
+		speechEnd := seg.EndTime
+		if len(seg.Words) > 0 {
+			speechEnd = 0
+			for _, word := range seg.Words {
+				speechEnd = max(speechEnd, word.EndTime)
+			}
+		}
+		lastSpoken = max(lastSpoken, speechEnd)
+	}
+	if lastSpoken >= end {
+		return 0
+	}
+
+	for i := range t.Segments {
+		seg := &t.Segments[i]
+		seg.EndTime = max(min(seg.EndTime, lastSpoken), seg.StartTime)
+	}
+	return end - t.Duration()
+}
+
+// MergeAdjacentSpeakers combines consecutive segments from the same speaker
+// when the gap between one segment's end and the next one's start is at most
+// maxGap seconds. Merged segments span the combined time range, their text is
+// joined with a single space, and their source IDs and word timings are
+// combined. Segments should be sorted by time first. It returns the number of
+// segments removed by merging.
+func (t *Transcript) MergeAdjacentSpeakers(maxGap float64) int {
+	if len(t.Segments) < 2 {
+		return 0
+	}
+
+	merged := t.Segments[:1]
+	for _, seg := range t.Segments[1:] {
+		last := &merged[len(merged)-1]
+		if seg.Speaker != last.Speaker || seg.StartTime-last.EndTime > maxGap {
+			merged = append(merged, seg)
+			continue
+		}
+
+		last.Text = strings.TrimSpace(last.Text) + " " + strings.TrimSpace(seg.Text)
+		if seg.EndTime > last.EndTime {
+			last.EndTime = seg.EndTime
+		}
+		last.Confidence = minConfidence(last.Confidence, seg.Confidence)
+		last.SourceIDs = unionIDs(last.SourceIDs, seg.SourceIDs)
+		last.Words = append(last.Words[:len(last.Words):len(last.Words)], seg.Words...)
+	}
+
+	removed := len(t.Segments) - len(merged)
+	t.Segments = merged
+	return removed
+}
+
+// repetitionMaxGap is the largest gap in seconds between two segments with
+// the same text for RemoveRepetitions to treat them as one repetition loop.
+// Looping whisper output is back to back, while a speaker saying "Yeah." again
+// a minute later is not a repetition.
+const repetitionMaxGap = 2.0
+
+// RemoveRepetitions collapses runs of segments from the same speaker with
+// identical text, the output of whisper stuck in a loop on silence or music,
+// keeping the first maxRepeats of each run (at least one). The last segment
+// kept is extended to the end of the run, so the time range is preserved,
+// and takes on the run's source IDs and lowest confidence. A run continues
+// while each repeat starts within repetitionMaxGap seconds of the previous
+// one ending; segments from other speakers in between, as from separate
+// tracks, do not break it. Segments should be sorted by time first. It
+// returns the number of segments removed.
+func (t *Transcript) RemoveRepetitions(maxRepeats int) int {
+	maxRepeats = max(maxRepeats, 1)
+
+	type run struct {
+		last    int // Index in kept of the speaker's latest kept segment
+		repeats int
+		end     float64 // End time of the run's latest segment
+	}
+	runs := make(map[string]*run)
+
+	kept := t.Segments[:0]
+	for _, seg := range t.Segments {
+		r := runs[seg.Speaker]
+		if r == nil || strings.TrimSpace(seg.Text) != strings.TrimSpace(kept[r.last].Text) || seg.StartTime-r.end > repetitionMaxGap {
+			kept = append(kept, seg)
+			runs[seg.Speaker] = &run{last: len(kept) - 1, repeats: 1, end: seg.EndTime}
+			continue
+		}
+
+		r.repeats++
+		r.end = max(r.end, seg.EndTime)
+		if r.repeats <= maxRepeats {
+			kept = append(kept, seg)
+			r.last = len(kept) - 1
+			continue
+		}
+		last := &kept[r.last]
+		last.EndTime = max(last.EndTime, seg.EndTime)
+		last.Confidence = minConfidence(last.Confidence, seg.Confidence)
+		last.SourceIDs = unionIDs(last.SourceIDs, seg.SourceIDs)
+	}
+
+	removed := len(t.Segments) - len(kept)
+	t.Segments = kept
+	return removed
+}
+
+// DefaultFillers are the filler words RemoveFillers removes when given none.
+// Words like "like" and "so" are left out since they are often meaningful.
+var DefaultFillers = []string{"um", "umm", "uh", "uhh", "er", "erm", "ah", "hmm", "mm"}
+
+// RemoveFillers deletes filler words such as "um" and "uh" from segment text
+// and word timings, matching case-insensitively and ignoring surrounding
+// punctuation. A sentence-ending mark on a removed word moves to the word
+// before it, and a capitalized filler that starts a segment capitalizes the
+// new first word. Segments left with no text are removed. A nil fillers uses
+// DefaultFillers. It returns the number of words removed.
+func (t *Transcript) RemoveFillers(fillers []string) int {
+	if fillers == nil {
+		fillers = DefaultFillers
+	}
+	isFiller := make(map[string]bool, len(fillers))
+	for _, f := range fillers {
+		isFiller[strings.ToLower(f)] = true
+	}
+	match := func(word string) bool {
+		return isFiller[strings.ToLower(strings.TrimFunc(word, unicode.IsPunct))]
+	}
+
+	removed := 0
+	kept := t.Segments[:0]
+	for _, seg := range t.Segments {
+		var words []string
+		fields := strings.Fields(seg.Text)
+		for i, word := range fields {
+			if !match(word) {
+				if i > 0 && len(words) == 0 && startsUpper(fields[0]) {
+					word = capitalize(word)
+				}
+				words = append(words, word)
+				continue
+			}
+			removed++
+			end := strings.TrimRightFunc(word, func(r rune) bool { return r != '.' && r != '?' && r != '!' })
+			if end != "" && len(words) > 0 {
+				last := strings.TrimRight(words[len(words)-1], ",;:")
+				words[len(words)-1] = last + word[len(end)-1:]
+			}
+		}
+		if len(words) == 0 {
+			continue
+		}
+		seg.Text = strings.Join(words, " ")
+		seg.Words = slices.DeleteFunc(seg.Words, func(w Word) bool { return match(strings.TrimSpace(w.Text)) })
+		kept = append(kept, seg)
+	}
+	t.Segments = kept
+	return removed
+}
+
+// startsUpper reports whether word begins with an upper-case letter
+func startsUpper(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(r)
+}
+
+// capitalize returns word with its first letter in upper case
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// SplitLongSegments divides every segment longer than maxSeconds into
+// ceil(duration/maxSeconds) consecutive segments at word boundaries. Split
+// points come from word timings when the segment has them; otherwise the
+// words are divided evenly and timestamps are interpolated linearly. Each
+// piece keeps the segment's speaker, source IDs, and confidence. Segments
+// with a single word are left as they are. It returns the number of segments
+// that were split.
+func (t *Transcript) SplitLongSegments(maxSeconds float64) int {
+	if maxSeconds <= 0 {
+		return 0
+	}
+
+	split := 0
+	segments := make([]Segment, 0, len(t.Segments))
+	for _, seg := range t.Segments {
+		pieces := splitSegment(seg, maxSeconds)
+		if len(pieces) > 1 {
+			split++
+		}
+		segments = append(segments, pieces...)
+	}
+	t.Segments = segments
+	return split
+}
+
+// splitSegment divides seg into pieces of at most about maxSeconds each
+func splitSegment(seg Segment, maxSeconds float64) []Segment {
+	words := strings.Fields(seg.Text)
+	duration := seg.Duration()
+	if duration <= maxSeconds || len(words) < 2 {
+		return []Segment{seg}
+	}
+
+	count := min(int(math.Ceil(duration/maxSeconds)), len(words))
+	timed := len(seg.Words) == len(words)
+
+	// bounds[i] is the index of the first word of piece i. With word timings,
+	// each piece starts at the first word beginning after its share of the
+	// duration, leaving at least one word for every later piece.
+	bounds := make([]int, count+1)
+	bounds[count] = len(words)
+	for i := 1; i < count; i++ {
+		bounds[i] = len(words) * i / count
+		if timed {
+			target := seg.StartTime + duration*float64(i)/float64(count)
+			k := bounds[i-1] + 1
+			for k < len(words)-(count-i) && seg.Words[k].StartTime < target {
+				k++
+			}
+			bounds[i] = k
+		}
+	}
+
+	pieces := make([]Segment, count)
+	for i := range pieces {
+		start, end := bounds[i], bounds[i+1]
+		piece := Segment{
+			Speaker:    seg.Speaker,
+			Text:       strings.Join(words[start:end], " "),
+			StartTime:  seg.StartTime + duration*float64(start)/float64(len(words)),
+			EndTime:    seg.StartTime + duration*float64(end)/float64(len(words)),
+			SourceIDs:  slices.Clone(seg.SourceIDs),
+			Confidence: seg.Confidence,
+			Source:     seg.Source,
+		}
+		if timed {
+			piece.Words = slices.Clone(seg.Words[start:end])
+			piece.StartTime = seg.Words[start].StartTime
+			piece.EndTime = seg.Words[end-1].EndTime
+		}
+		if i == 0 {
+			piece.StartTime = seg.StartTime
+		}
+		if i == count-1 {
+			piece.EndTime = seg.EndTime
+		}
+		pieces[i] = piece
+	}
+	return pieces
+}
+
+// minConfidence returns the lower of two confidences, ignoring unknown (zero)
+// values, so merged segments still surface in LowConfidenceSegments
+func minConfidence(a, b float64) float64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// unionIDs returns the IDs in a followed by those in b not already present
+func unionIDs(a, b []int) []int {
+	result := a[:len(a):len(a)]
+	for _, id := range b {
+		if !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// Shift adds seconds to every segment and word timestamp, for aligning a
+// transcript with an edit that added (positive) or removed (negative) leading
+// content. Timestamps that would become negative are clamped to 0.
+func (t *Transcript) Shift(seconds float64) {
+	shift := func(ts *float64) {
+		*ts = max(*ts+seconds, 0)
+	}
+	for i := range t.Segments {
+		seg := &t.Segments[i]
+		shift(&seg.StartTime)
+		shift(&seg.EndTime)
+		for j := range seg.Words {
+			shift(&seg.Words[j].StartTime)
+			shift(&seg.Words[j].EndTime)
+		}
+	}
+}
+
 // FormatTime converts seconds to a time.Duration
 func FormatTime(seconds float64) time.Duration {
 	return time.Duration(seconds * float64(time.Second))
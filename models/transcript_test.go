@@ -0,0 +1,293 @@
+package models
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestTranscriptDurationAndSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		segments  []Segment
+		duration  float64
+		spanStart float64
+		spanEnd   float64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:      "single zero-length segment at nonzero time",
+			segments:  []Segment{{StartTime: 42.5, EndTime: 42.5}},
+			duration:  42.5,
+			spanStart: 42.5,
+			spanEnd:   42.5,
+		},
+		{
+			name:     "all segments at zero",
+			segments: []Segment{{StartTime: 0, EndTime: 0}, {StartTime: 0, EndTime: 0}},
+		},
+		{
+			name:      "offset segments",
+			segments:  []Segment{{StartTime: 30, EndTime: 35}, {StartTime: 10, EndTime: 20}},
+			duration:  35,
+			spanStart: 10,
+			spanEnd:   35,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := NewTranscript()
+			transcript.AddSegments(tt.segments)
+
+			if got := transcript.Duration(); got != tt.duration {
+				t.Errorf("Duration() = %g, want %g", got, tt.duration)
+			}
+			start, end := transcript.Span()
+			if start != tt.spanStart || end != tt.spanEnd {
+				t.Errorf("Span() = (%g, %g), want (%g, %g)", start, end, tt.spanStart, tt.spanEnd)
+			}
+		})
+	}
+}
+
+func TestRemoveFillers(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string // "" if the segment is removed
+		removed int
+	}{
+		{"no fillers", "We shipped it.", "We shipped it.", 0},
+		{"leading filler", "Um, so we shipped it.", "So we shipped it.", 1},
+		{"middle fillers", "We, uh, shipped it, um, yesterday.", "We, shipped it, yesterday.", 2},
+		{"trailing filler keeps sentence end", "We shipped it, uh.", "We shipped it.", 1},
+		{"question mark moves", "Did we ship it, um?", "Did we ship it?", 1},
+		{"case-insensitive", "UH we shipped it", "We shipped it", 1},
+		{"lowercase start stays lowercase", "uh, we shipped it", "we shipped it", 1},
+		{"only fillers", "Um. Uh...", "", 2},
+		{"filler inside word untouched", "Umbrella and hummus", "Umbrella and hummus", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := NewTranscript()
+			transcript.AddSegment(Segment{Speaker: "Alice", Text: tt.text, StartTime: 0, EndTime: 1})
+
+			if removed := transcript.RemoveFillers(nil); removed != tt.removed {
+				t.Errorf("RemoveFillers() = %d, want %d", removed, tt.removed)
+			}
+			if tt.want == "" {
+				if len(transcript.Segments) != 0 {
+					t.Errorf("segment kept as %q, want it removed", transcript.Segments[0].Text)
+				}
+				return
+			}
+			if len(transcript.Segments) != 1 {
+				t.Fatalf("got %d segments, want 1", len(transcript.Segments))
+			}
+			if got := transcript.Segments[0].Text; got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveFillersWords(t *testing.T) {
+	transcript := NewTranscript()
+	transcript.AddSegment(Segment{
+		Speaker: "Alice",
+		Text:    "So, um, hello",
+		Words:   []Word{{Text: "So,"}, {Text: " um,"}, {Text: " hello"}},
+	})
+	transcript.RemoveFillers([]string{"UM"})
+
+	words := transcript.Segments[0].Words
+	if len(words) != 2 || words[0].Text != "So," || words[1].Text != " hello" {
+		t.Errorf("words = %+v, want So, and hello", words)
+	}
+}
+
+func TestSegmentDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end float64
+		want       float64
+	}{
+		{"ordinary", 1.5, 4, 2.5},
+		{"zero length", 3, 3, 0},
+		{"reversed times clamp to zero", 5, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := Segment{StartTime: tt.start, EndTime: tt.end}
+			if got := seg.Duration(); got != tt.want {
+				t.Errorf("Duration() = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsIgnoresReversedSegments(t *testing.T) {
+	transcript := NewTranscript()
+	transcript.AddSegments([]Segment{
+		{Speaker: "Alice", Text: "hello", StartTime: 0, EndTime: 2},
+		{Speaker: "Alice", Text: "oops", StartTime: 10, EndTime: 4},
+	})
+	if got := transcript.Stats()["Alice"].TotalSpeakingTime; got != 2 {
+		t.Errorf("TotalSpeakingTime = %g, want 2", got)
+	}
+	if errs := transcript.Validate(); len(errs) != 1 {
+		t.Errorf("Validate() = %v, want one error for the reversed segment", errs)
+	}
+}
+
+func TestRenameSpeakersCollisions(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     map[string]string
+		wantRoles map[string]string
+		wantLangs map[string]string
+	}{
+		{
+			name:      "speaker keeping its label wins",
+			names:     map[string]string{"C": "A", "B": "A"},
+			wantRoles: map[string]string{"A": "Host"},
+			wantLangs: map[string]string{"A": "en"},
+		},
+		{
+			name:      "first old label wins when none keep it",
+			names:     map[string]string{"C": "D", "B": "D", "A": "E"},
+			wantRoles: map[string]string{"D": "Guest", "E": "Host"},
+			wantLangs: map[string]string{"D": "fr", "E": "en"},
+		},
+		{
+			name:      "swap",
+			names:     map[string]string{"A": "B", "B": "A"},
+			wantRoles: map[string]string{"A": "Guest", "B": "Host", "C": "Producer"},
+			wantLangs: map[string]string{"A": "fr", "B": "en", "C": "de"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat so a dependence on map iteration order shows up
+			for range 20 {
+				transcript := NewTranscript()
+				for speaker, role := range map[string]string{"A": "Host", "B": "Guest", "C": "Producer"} {
+					transcript.SetSpeakerRole(speaker, role)
+				}
+				for speaker, lang := range map[string]string{"A": "en", "B": "fr", "C": "de"} {
+					transcript.SetDetectedLanguage(speaker, lang)
+				}
+				transcript.RenameSpeakers(tt.names)
+
+				roles := make(map[string]string)
+				for speaker, info := range transcript.Speakers {
+					roles[speaker] = info.Role
+				}
+				if !maps.Equal(roles, tt.wantRoles) {
+					t.Fatalf("roles = %v, want %v", roles, tt.wantRoles)
+				}
+				if !maps.Equal(transcript.DetectedLanguages, tt.wantLangs) {
+					t.Fatalf("detected languages = %v, want %v", transcript.DetectedLanguages, tt.wantLangs)
+				}
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	original := NewTranscript()
+	original.AddSegment(Segment{
+		Speaker:   "Alice",
+		Text:      "Hello there",
+		StartTime: 0,
+		EndTime:   1,
+		SourceIDs: []int{0, 1},
+		Words:     []Word{{Text: "Hello", EndTime: 0.5}, {Text: "there", StartTime: 0.5, EndTime: 1}},
+	})
+	original.SetSpeakerRole("Alice", "Host")
+	original.SetDetectedLanguage("Alice", "en")
+	original.Model = "base"
+
+	clone := original.Clone()
+	clone.Segments[0].Text = "Changed"
+	clone.Segments[0].SourceIDs[0] = 9
+	clone.Segments[0].Words[0].Text = "Changed"
+	clone.SetSpeakerRole("Alice", "Guest")
+	clone.SetDetectedLanguage("Alice", "fr")
+	clone.AddSegment(Segment{Speaker: "Bob", Text: "Hi", StartTime: 1, EndTime: 2})
+
+	seg := original.Segments[0]
+	if len(original.Segments) != 1 || seg.Text != "Hello there" || seg.SourceIDs[0] != 0 || seg.Words[0].Text != "Hello" {
+		t.Errorf("changing the clone changed the original segments: %+v", original.Segments)
+	}
+	if original.Speakers["Alice"].Role != "Host" || original.DetectedLanguages["Alice"] != "en" {
+		t.Errorf("changing the clone changed the original metadata: %v %v", original.Speakers, original.DetectedLanguages)
+	}
+	if clone.Model != "base" {
+		t.Errorf("clone model = %q, want %q", clone.Model, "base")
+	}
+}
+
+func TestTrimTrailingSilence(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []Segment
+		removed  float64
+		ends     []float64
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "final segment padded past its last word",
+			segments: []Segment{
+				{StartTime: 0, EndTime: 4, Words: []Word{{Text: "Hi", EndTime: 1}}},
+				{StartTime: 5, EndTime: 12, Words: []Word{{Text: "Bye", StartTime: 5, EndTime: 6.5}}},
+			},
+			removed: 5.5,
+			ends:    []float64{4, 6.5},
+		},
+		{
+			name: "overlapping segment clamped to the last word",
+			segments: []Segment{
+				{StartTime: 0, EndTime: 10, Words: []Word{{Text: "Hi", EndTime: 3}}},
+				{StartTime: 2, EndTime: 8, Words: []Word{{Text: "Bye", StartTime: 2, EndTime: 7}}},
+			},
+			removed: 3,
+			ends:    []float64{7, 7},
+		},
+		{
+			name: "segment without word timings counts as speech",
+			segments: []Segment{
+				{StartTime: 0, EndTime: 9},
+				{StartTime: 1, EndTime: 10, Words: []Word{{Text: "Hi", StartTime: 1, EndTime: 2}}},
+			},
+			removed: 1,
+			ends:    []float64{9, 9},
+		},
+		{
+			name:     "no trailing silence",
+			segments: []Segment{{StartTime: 0, EndTime: 2, Words: []Word{{Text: "Hi", EndTime: 2}}}},
+			ends:     []float64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := NewTranscript()
+			transcript.AddSegments(tt.segments)
+
+			if got := transcript.TrimTrailingSilence(); got != tt.removed {
+				t.Errorf("TrimTrailingSilence() = %g, want %g", got, tt.removed)
+			}
+			for i, seg := range transcript.Segments {
+				if seg.EndTime != tt.ends[i] {
+					t.Errorf("segment %d ends at %g, want %g", i, seg.EndTime, tt.ends[i])
+				}
+			}
+		})
+	}
+}
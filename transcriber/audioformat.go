@@ -0,0 +1,267 @@
+package transcriber
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-audio/wav"
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/mewkiz/flac"
+)
+
+// mp3HeaderSearchLimit bounds how far past any ID3 tag ReadAudioInfo looks
+// for the first MPEG frame header
+const mp3HeaderSearchLimit = 64 * 1024
+
+// oggTailSize is how much of the end of an Ogg file is searched for the
+// last page, whose granule position gives the stream length
+const oggTailSize = 64 * 1024
+
+// AudioInfo describes an audio file as recorded in its headers
+type AudioInfo struct {
+	Container  string  // "wav", "mp3", "flac", "vorbis", or "opus"
+	Duration   float64 // Length in seconds, or 0 if the headers don't record it
+	SampleRate int     // Sample rate in Hz; for Opus, the rate of the original input
+	Channels   int     // Number of channels
+	BitDepth   int     // Bits per sample of the decoded audio
+}
+
+// String describes the sample format, e.g. "WAV, 48000 Hz, 24 bit, 2 channel(s)"
+func (f AudioInfo) String() string {
+	return fmt.Sprintf("%s, %d Hz, %d bit, %d channel(s)",
+		strings.ToUpper(f.Container), f.SampleRate, f.BitDepth, f.Channels)
+}
+
+// ReadAudioInfo reads the format and duration of an audio file without
+// decoding its audio. WAV and FLAC durations come from their headers, Ogg
+// durations from the stream's last page, and MP3 durations from a scan of
+// the frame headers.
+func ReadAudioInfo(audioPath string) (AudioInfo, error) {
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return AudioInfo{}, fmt.Errorf("failed to open audio file: %w", err)
+	}
+	defer file.Close()
+
+	return decodeContainer(file, audioPath, func(container string) (AudioInfo, error) {
+		switch container {
+		case containerMP3:
+			info, err := readMP3Format(file)
+			if err != nil {
+				return AudioInfo{}, err
+			}
+			info.Duration, err = mp3Duration(file)
+			if err != nil {
+				return AudioInfo{}, err
+			}
+			return info, nil
+		case containerOgg:
+			header, err := readOggHeader(bufio.NewReader(file))
+			if err != nil {
+				return AudioInfo{}, err
+			}
+			duration, err := oggDuration(file, header)
+			if err != nil {
+				return AudioInfo{}, err
+			}
+			// Opus always decodes at 48kHz, so report the original input rate
+			sampleRate := header.sampleRate
+			if header.inputRate > 0 {
+				sampleRate = header.inputRate
+			}
+			return AudioInfo{
+				Container:  header.codec,
+				Duration:   duration,
+				SampleRate: sampleRate,
+				Channels:   header.channels,
+				BitDepth:   16,
+			}, nil
+		case containerFLAC:
+			stream, err := flac.New(file)
+			if err != nil {
+				return AudioInfo{}, fmt.Errorf("invalid FLAC file: %w", err)
+			}
+			defer stream.Close()
+			info := AudioInfo{
+				Container:  containerFLAC,
+				SampleRate: int(stream.Info.SampleRate),
+				Channels:   int(stream.Info.NChannels),
+				BitDepth:   int(stream.Info.BitsPerSample),
+			}
+			// A sample count of 0 means the encoder didn't record it
+			if info.SampleRate > 0 {
+				info.Duration = float64(stream.Info.NSamples) / float64(info.SampleRate)
+			}
+			return info, nil
+		}
+
+		decoder := wav.NewDecoder(file)
+		if !decoder.IsValidFile() {
+			return AudioInfo{}, fmt.Errorf("invalid WAV file: %s", audioPath)
+		}
+		duration, err := decoder.Duration()
+		if err != nil {
+			return AudioInfo{}, fmt.Errorf("invalid WAV file: %w", err)
+		}
+		return AudioInfo{
+			Container:  containerWAV,
+			Duration:   duration.Seconds(),
+			SampleRate: int(decoder.SampleRate),
+			Channels:   int(decoder.NumChans),
+			BitDepth:   int(decoder.BitDepth),
+		}, nil
+	})
+}
+
+// mp3Duration returns the length of an MP3 file in seconds. The decoder
+// indexes every frame header when opened on a seekable file, which gives the
+// decoded length without decoding any audio.
+func mp3Duration(file *os.File) (float64, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	decoder, err := mp3.NewDecoder(file)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MP3 file: %w", err)
+	}
+	if decoder.Length() <= 0 || decoder.SampleRate() <= 0 {
+		return 0, nil
+	}
+	// Decoded frames are 16-bit stereo, 4 bytes each
+	return float64(decoder.Length()) / 4 / float64(decoder.SampleRate()), nil
+}
+
+// oggDuration returns the length of an Ogg stream in seconds from the
+// granule position of its last page, which counts the samples decoded by the
+// end of the stream. Opus granules run at 48kHz and include the pre-skip
+// samples the decoder discards.
+func oggDuration(file *os.File, header oggHeader) (float64, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	start := max(stat.Size()-oggTailSize, 0)
+	tail := make([]byte, stat.Size()-start)
+	if _, err := file.ReadAt(tail, start); err != nil && err != io.EOF {
+		return 0, fmt.Errorf("failed to read audio file: %w", err)
+	}
+
+	// Pages with no packet ending on them have a granule position of -1
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+14 > len(tail) {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(tail[i+6 : i+14]))
+		if granule < 0 {
+			continue
+		}
+		samples := granule - int64(header.preSkip)
+		if samples <= 0 || header.sampleRate <= 0 {
+			return 0, nil
+		}
+		return float64(samples) / float64(header.sampleRate), nil
+	}
+	return 0, nil
+}
+
+// readMP3Format reads the sample rate and channel count from the first MPEG
+// frame header. The MP3 decoder always produces 16-bit stereo, so the channel
+// count is taken from the frame header rather than the decoder.
+func readMP3Format(r io.Reader) (AudioInfo, error) {
+	br := bufio.NewReader(r)
+
+	// Skip an ID3v2 tag, whose size is stored as a 28-bit syncsafe integer
+	if header, err := br.Peek(10); err == nil && string(header[:3]) == "ID3" {
+		size := int(header[6])<<21 | int(header[7])<<14 | int(header[8])<<7 | int(header[9])
+		if _, err := br.Discard(10 + size); err != nil {
+			return AudioInfo{}, fmt.Errorf("invalid MP3 file: %w", err)
+		}
+	}
+
+	sampleRates := map[byte][3]int{
+		3: {44100, 48000, 32000}, // MPEG 1
+		2: {22050, 24000, 16000}, // MPEG 2
+		0: {11025, 12000, 8000},  // MPEG 2.5
+	}
+
+	for skipped := 0; skipped < mp3HeaderSearchLimit; skipped++ {
+		header, err := br.Peek(4)
+		if err != nil {
+			break
+		}
+		if header[0] == 0xFF && header[1]&0xE0 == 0xE0 {
+			rates, ok := sampleRates[(header[1]>>3)&0x03]
+			rateIndex := (header[2] >> 2) & 0x03
+			if ok && rateIndex < 3 {
+				channels := 2
+				if header[3]>>6 == 0x03 {
+					channels = 1
+				}
+				return AudioInfo{
+					Container:  containerMP3,
+					SampleRate: rates[rateIndex],
+					Channels:   channels,
+					BitDepth:   16,
+				}, nil
+			}
+		}
+		if _, err := br.Discard(1); err != nil {
+			break
+		}
+	}
+
+	return AudioInfo{}, fmt.Errorf("invalid MP3 file: no frame header found")
+}
+
+// FormatMismatchError reports audio files whose sample formats disagree
+type FormatMismatchError struct {
+	Paths   []string // Every checked file, in order
+	Formats []string // Description of each file's format
+}
+
+func (e *FormatMismatchError) Error() string {
+	files := make([]string, len(e.Paths))
+	for i, path := range e.Paths {
+		files[i] = fmt.Sprintf("%s (%s)", path, e.Formats[i])
+	}
+	return "audio files disagree on sample rate or channel count: " + strings.Join(files, ", ")
+}
+
+// CheckAudioFormats reads the header of each audio file and returns a
+// *FormatMismatchError if the files disagree on sample rate or channel count.
+// Tracks from the same recording session should match, so a mismatch usually
+// means the wrong file was picked. Standard input is skipped, since reading
+// its header would consume it.
+func CheckAudioFormats(audioFiles []AudioFile) error {
+	mismatch := &FormatMismatchError{}
+	var first AudioInfo
+	differ := false
+
+	for _, af := range audioFiles {
+		if af.Path == StdinPath {
+			continue
+		}
+		format, err := ReadAudioInfo(af.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", af.Path, err)
+		}
+
+		if len(mismatch.Paths) == 0 {
+			first = format
+		} else if format.SampleRate != first.SampleRate || format.Channels != first.Channels {
+			differ = true
+		}
+		mismatch.Paths = append(mismatch.Paths, af.Path)
+		mismatch.Formats = append(mismatch.Formats, format.String())
+	}
+
+	if !differ {
+		return nil
+	}
+	return mismatch
+}
@@ -0,0 +1,170 @@
+package transcriber
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"skriptble.dev/podcast-tools/models"
+
+	whisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
+)
+
+const (
+	// energyWindow is the number of samples per energy envelope value (100ms)
+	energyWindow = whisper.SampleRate / 10
+
+	// bleedMinOverlap is the fraction of the shorter segment that must overlap
+	// another track's segment for it to be considered bleed
+	bleedMinOverlap = 0.5
+
+	// bleedMinSimilarity is the minimum word similarity (0-1) between two
+	// overlapping segments for them to be considered the same speech
+	bleedMinSimilarity = 0.6
+)
+
+// energyEnvelope returns the RMS level of each energyWindow of samples
+func energyEnvelope(samples []float32) []float32 {
+	envelope := make([]float32, 0, len(samples)/energyWindow+1)
+	for start := 0; start < len(samples); start += energyWindow {
+		end := start + energyWindow
+		if end > len(samples) {
+			end = len(samples)
+		}
+
+		sum := 0.0
+		for _, sample := range samples[start:end] {
+			sum += float64(sample) * float64(sample)
+		}
+		envelope = append(envelope, float32(math.Sqrt(sum/float64(end-start))))
+	}
+	return envelope
+}
+
+// meanEnergy returns the average envelope level between start and end seconds
+func meanEnergy(envelope []float32, start, end float64) float64 {
+	windowsPerSecond := float64(whisper.SampleRate) / energyWindow
+	first := int(start * windowsPerSecond)
+	last := int(math.Ceil(end * windowsPerSecond))
+	if first < 0 {
+		first = 0
+	}
+	if last > len(envelope) {
+		last = len(envelope)
+	}
+	if first >= last {
+		return 0
+	}
+
+	sum := 0.0
+	for _, level := range envelope[first:last] {
+		sum += float64(level)
+	}
+	return sum / float64(last-first)
+}
+
+// SuppressBleed is a Transform that removes segments duplicating an
+// overlapping segment from another speaker, keeping the one whose audio was
+// louder during the overlap. It is the step ProcessConfig.SuppressBleed runs,
+// for transcripts built from files transcribed separately, such as the two
+// channels of a stereo recording loaded with LoadAudioChannel. Every
+// speaker in the transcript must have audio on the same timeline.
+type SuppressBleed struct {
+	Audio map[string][]float32 // Each speaker's 16kHz mono samples, as returned by LoadAudio
+}
+
+// Apply implements Transform
+func (s SuppressBleed) Apply(transcript *models.Transcript) error {
+	envelopes := make(map[string][]float32, len(s.Audio))
+	for _, speaker := range transcript.SpeakerNames() {
+		audio, ok := s.Audio[speaker]
+		if !ok {
+			return fmt.Errorf("no audio for speaker %q", speaker)
+		}
+		envelopes[speaker] = energyEnvelope(audio)
+	}
+	transcript.SortByTime()
+	suppressBleed(transcript, envelopes)
+	return nil
+}
+
+// suppressBleed removes segments that duplicate an overlapping segment from
+// another speaker's track, keeping whichever track had more energy during the
+// overlap. Segments must be sorted by start time. Returns the number removed.
+func suppressBleed(transcript *models.Transcript, envelopes map[string][]float32) int {
+	segments := transcript.Segments
+	drop := make([]bool, len(segments))
+
+	for i := range segments {
+		if drop[i] {
+			continue
+		}
+		for j := i + 1; j < len(segments) && segments[j].StartTime < segments[i].EndTime; j++ {
+			if drop[j] || segments[j].Speaker == segments[i].Speaker {
+				continue
+			}
+
+			overlapStart := math.Max(segments[i].StartTime, segments[j].StartTime)
+			overlapEnd := math.Min(segments[i].EndTime, segments[j].EndTime)
+			shorter := math.Min(segments[i].Duration(), segments[j].Duration())
+			if shorter <= 0 || overlapEnd-overlapStart < bleedMinOverlap*shorter {
+				continue
+			}
+			if textSimilarity(segments[i].Text, segments[j].Text) < bleedMinSimilarity {
+				continue
+			}
+
+			// Attribute the speech to the louder track
+			energyI := meanEnergy(envelopes[segments[i].Speaker], overlapStart, overlapEnd)
+			energyJ := meanEnergy(envelopes[segments[j].Speaker], overlapStart, overlapEnd)
+			if energyJ > energyI {
+				drop[i] = true
+				break
+			}
+			drop[j] = true
+		}
+	}
+
+	kept := segments[:0]
+	removed := 0
+	for i, seg := range segments {
+		if drop[i] {
+			removed++
+			continue
+		}
+		kept = append(kept, seg)
+	}
+	transcript.Segments = kept
+	return removed
+}
+
+// textSimilarity returns the Dice coefficient (0-1) of the words in a and b,
+// ignoring case and punctuation
+func textSimilarity(a, b string) float64 {
+	wordsA := normalizedWords(a)
+	wordsB := normalizedWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int)
+	for _, word := range wordsA {
+		counts[word]++
+	}
+	common := 0
+	for _, word := range wordsB {
+		if counts[word] > 0 {
+			counts[word]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
+
+// normalizedWords splits text into lowercase words without punctuation
+func normalizedWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
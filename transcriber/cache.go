@@ -0,0 +1,174 @@
+package transcriber
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// cacheVersion is included in every cache key so entries written by an older
+// version of the cache format are never read back
+const cacheVersion = 1
+
+// cacheEntry is the JSON stored for one cached transcription
+type cacheEntry struct {
+	Language string           `json:"language"`
+	Segments []models.Segment `json:"segments"`
+}
+
+// cacheParams are the settings besides the audio that change whisper's
+// output, hashed into the cache key. The model is identified by its path,
+// size, and modification time rather than its contents, which would take
+// seconds to hash for the larger models.
+type cacheParams struct {
+	Version       int       `json:"version"`
+	ModelPath     string    `json:"model_path"`
+	ModelSize     int64     `json:"model_size"`
+	ModelModTime  time.Time `json:"model_mod_time"`
+	Language      string    `json:"language"`
+	Temperature   float32   `json:"temperature"`
+	Translate     bool      `json:"translate"`
+	InitialPrompt string    `json:"initial_prompt"`
+}
+
+// DefaultCacheDir returns the default directory for cached transcriptions
+// under the user's cache directory (e.g., ~/.cache/podcast-tools/transcripts
+// on Linux), or "" if it cannot be determined
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "podcast-tools", "transcripts")
+}
+
+// transcribeAudioCached is transcribeAudioWithRetry with a lookup in
+// config.CacheDir first. The audio passed in is what whisper would see,
+// after normalization and silence trimming, so changing those settings or
+// the file changes the key. Cache problems never fail a transcription: an
+// unreadable entry is treated as a miss and a failed write only logged.
+func (wt *WhisperTranscriber) transcribeAudioCached(ctx context.Context, audioData []float32, speakerLabel string, language string, maxRetries int) ([]models.Segment, string, error) {
+	if wt.config.CacheDir == "" {
+		return wt.transcribeAudioWithRetry(ctx, audioData, speakerLabel, language, maxRetries)
+	}
+
+	key, err := wt.cacheKey(audioData, language)
+	if err != nil {
+		wt.logger.Warn("Transcription cache disabled", "error", err)
+		return wt.transcribeAudioWithRetry(ctx, audioData, speakerLabel, language, maxRetries)
+	}
+	path := filepath.Join(wt.config.CacheDir, key+".json")
+
+	if entry, ok := readCacheEntry(path); ok {
+		wt.logger.Info("Using cached transcription", "speaker", speakerLabel, "path", path)
+		// Entries are shared by any file with the same audio, whatever its speaker
+		for i := range entry.Segments {
+			entry.Segments[i].Speaker = speakerLabel
+		}
+		return entry.Segments, entry.Language, nil
+	}
+
+	segments, detected, err := wt.transcribeAudioWithRetry(ctx, audioData, speakerLabel, language, maxRetries)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := writeCacheEntry(path, cacheEntry{Language: detected, Segments: segments}); err != nil {
+		wt.logger.Warn("Could not cache transcription", "path", path, "error", err)
+	}
+	return segments, detected, nil
+}
+
+// cacheKey returns the hex SHA-256 digest of the transcription settings and
+// the audio samples. A non-empty language overrides the configured one, as
+// in transcribeAudio.
+func (wt *WhisperTranscriber) cacheKey(audioData []float32, language string) (string, error) {
+	info, err := os.Stat(wt.config.ModelPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read model file: %w", err)
+	}
+	modelPath, err := filepath.Abs(wt.config.ModelPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve model path: %w", err)
+	}
+	if language == "" {
+		language = wt.config.Language
+	}
+
+	params, err := json.Marshal(cacheParams{
+		Version:       cacheVersion,
+		ModelPath:     modelPath,
+		ModelSize:     info.Size(),
+		ModelModTime:  info.ModTime().UTC(),
+		Language:      language,
+		Temperature:   wt.config.Temperature,
+		Translate:     wt.config.Translate,
+		InitialPrompt: wt.config.InitialPrompt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	hasher.Write(params)
+	buf := make([]byte, 0, 4*streamChunkFrames)
+	for len(audioData) > 0 {
+		n := min(len(audioData), streamChunkFrames)
+		buf = buf[:0]
+		for _, sample := range audioData[:n] {
+			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(sample))
+		}
+		hasher.Write(buf)
+		audioData = audioData[n:]
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// readCacheEntry reads the cache entry at path. The boolean is false if
+// there is no entry or it cannot be read.
+func readCacheEntry(path string) (cacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// writeCacheEntry writes entry to path through a temporary file renamed into
+// place, so concurrent runs never read a partial entry
+func writeCacheEntry(path string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name()) // No-op once renamed into place
+
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
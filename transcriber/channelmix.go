@@ -0,0 +1,62 @@
+package transcriber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChannelMix selects how multichannel audio is reduced to the single
+// channel whisper transcribes: MixAverage, MixLeft, MixRight, or a 1-based
+// channel number such as "3"
+type ChannelMix string
+
+// Channel mix modes
+const (
+	MixAverage ChannelMix = "average" // Average all channels (the default)
+	MixLeft    ChannelMix = "left"    // Use only the first channel
+	MixRight   ChannelMix = "right"   // Use only the second channel
+)
+
+// ParseChannelMix parses a channel mix mode, accepting the names of the
+// modes case-insensitively or a channel number of 1 or more. An empty string
+// is MixAverage.
+func ParseChannelMix(s string) (ChannelMix, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch ChannelMix(s) {
+	case "", MixAverage:
+		return MixAverage, nil
+	case MixLeft, MixRight:
+		return ChannelMix(s), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 {
+		return ChannelMix(s), nil
+	}
+	return "", fmt.Errorf("invalid channel mix '%s': expected average, left, right, or a channel number", s)
+}
+
+// channel returns the 0-based index of the channel to keep from audio with
+// numChannels channels, or -1 to average them. Mono audio always uses its
+// only channel, but a numbered channel beyond numChannels is an error.
+func (m ChannelMix) channel(numChannels int) (int, error) {
+	switch m {
+	case "", MixAverage:
+		return -1, nil
+	case MixLeft:
+		return 0, nil
+	case MixRight:
+		if numChannels < 2 {
+			return 0, nil
+		}
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(string(m))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid channel mix '%s'", m)
+	}
+	if n > numChannels {
+		return 0, fmt.Errorf("channel %d requested but the audio has %d channel(s)", n, numChannels)
+	}
+	return n - 1, nil
+}
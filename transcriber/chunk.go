@@ -0,0 +1,101 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// chunkOverlap is the number of seconds consecutive chunks of a file share,
+// so speech cut off at the end of one chunk is heard whole in the next
+const chunkOverlap = 5.0
+
+// audioChunk is a time window of a file's audio transcribed as its own job
+type audioChunk struct {
+	audio  []float32 // Samples in the window
+	offset float64   // Start of the window within the file in seconds
+	index  int       // Position of the chunk within the file, from 0
+	count  int       // Number of chunks the file was split into
+
+	// Segments are kept only when their midpoint falls in [keepFrom, keepTo).
+	// The boundary sits in the middle of each overlap, so a segment heard by
+	// two neighboring chunks is kept from just one of them.
+	keepFrom float64
+	keepTo   float64
+}
+
+// splitChunks divides audio into windows of chunkSeconds plus chunkOverlap
+// seconds, starting every chunkSeconds. The audio starts lead seconds into
+// the file. The windows share the underlying samples rather than copying them.
+func splitChunks(audio []float32, chunkSeconds int, lead float64) []audioChunk {
+	step := chunkSeconds * SampleRate
+	overlap := int(chunkOverlap * SampleRate)
+
+	var chunks []audioChunk
+	for start := 0; ; start += step {
+		end := min(start+step+overlap, len(audio))
+		last := end == len(audio)
+
+		chunk := audioChunk{
+			audio:    audio[start:end],
+			offset:   lead + float64(start)/SampleRate,
+			index:    len(chunks),
+			keepFrom: math.Inf(-1),
+			keepTo:   math.Inf(1),
+		}
+		if start > 0 {
+			chunk.keepFrom = chunk.offset + chunkOverlap/2
+		}
+		if !last {
+			chunk.keepTo = lead + float64(start+step)/SampleRate + chunkOverlap/2
+		}
+		chunks = append(chunks, chunk)
+
+		if last {
+			break
+		}
+	}
+
+	for i := range chunks {
+		chunks[i].count = len(chunks)
+	}
+	return chunks
+}
+
+// transcribeChunk transcribes one chunk of file and returns the segments it
+// keeps, with timestamps relative to the start of the file, and the language
+// whisper transcribed the chunk in. A failed chunk is retried up to
+// maxRetries times.
+func (wt *WhisperTranscriber) transcribeChunk(ctx context.Context, file AudioFile, chunk audioChunk, maxRetries int) ([]models.Segment, string, error) {
+	wt.logger.Info("Transcribing chunk", "chunk", chunk.index+1, "chunks", chunk.count,
+		"file", file.Path, "speaker", file.Speaker)
+
+	segments, detected, err := wt.transcribeAudioCached(ctx, chunk.audio, file.Speaker, file.Language, maxRetries)
+	if err != nil {
+		return nil, "", fmt.Errorf("chunk %d: %w", chunk.index+1, err)
+	}
+
+	shiftSegments(segments, chunk.offset)
+	setSource(segments, file.Path)
+
+	kept := segments[:0]
+	for _, seg := range segments {
+		midpoint := (seg.StartTime + seg.EndTime) / 2
+		if midpoint < chunk.keepFrom || midpoint >= chunk.keepTo {
+			continue
+		}
+		kept = append(kept, seg)
+	}
+	return kept, detected, nil
+}
+
+// renumberSourceIDs replaces the source IDs of a file's stitched segments
+// with their position in time order, since whisper numbers each chunk's
+// segments from zero
+func renumberSourceIDs(segments []models.Segment) {
+	for i := range segments {
+		segments[i].SourceIDs = []int{i}
+	}
+}
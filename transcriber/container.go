@@ -0,0 +1,107 @@
+package transcriber
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Audio containers recognized by decodeContainer
+const (
+	containerWAV  = "wav"
+	containerMP3  = "mp3"
+	containerFLAC = "flac"
+	containerOgg  = "ogg"
+)
+
+// IsSupportedAudioFile reports whether path has the extension of an audio
+// container the transcriber can decode
+func IsSupportedAudioFile(path string) bool {
+	return isContainer(containerFromExtension(path))
+}
+
+// isContainer reports whether name is one of the audio containers above
+func isContainer(name string) bool {
+	switch name {
+	case containerWAV, containerMP3, containerFLAC, containerOgg:
+		return true
+	}
+	return false
+}
+
+// containerFromExtension returns the container named by path's extension.
+// Ogg files are commonly named .oga or .opus as well as .ogg.
+func containerFromExtension(path string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch ext {
+	case "oga", "opus":
+		return containerOgg
+	}
+	return ext
+}
+
+// decodeContainer calls decode with file's container as named by path's
+// extension. The header's magic bytes are only consulted when the extension
+// is not a known container, or when decoding as that container fails, so a
+// misnamed file still decodes as what it actually holds. file must be at
+// its start, and is rewound before decode is called again.
+func decodeContainer[T any](file *os.File, path string, decode func(container string) (T, error)) (T, error) {
+	container := containerFromExtension(path)
+	if !isContainer(container) {
+		sniffed, err := sniffContainer(file)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		if sniffed != "" {
+			container = sniffed
+		}
+		return decode(container)
+	}
+
+	result, err := decode(container)
+	if err == nil {
+		return result, nil
+	}
+	sniffed, sniffErr := sniffContainer(file)
+	if sniffErr != nil || sniffed == "" || sniffed == container {
+		return result, err
+	}
+	return decode(sniffed)
+}
+
+// sniffContainer identifies the audio container of file from the magic
+// bytes at the start of its header, returning "" if they are not
+// recognized. The file is rewound to the start before returning.
+func sniffContainer(file *os.File) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind audio file: %w", err)
+	}
+	header := make([]byte, 12)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read audio header: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind audio file: %w", err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("RIFF")):
+		return containerWAV, nil
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return containerMP3, nil
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return containerFLAC, nil
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return containerOgg, nil
+	case len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0:
+		// MPEG audio frame sync without an ID3 tag
+		return containerMP3, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,86 @@
+package transcriber
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// renameTestFile moves path to a file called name in the same directory
+func renameTestFile(t *testing.T, path, name string) string {
+	t.Helper()
+	renamed := filepath.Join(filepath.Dir(path), name)
+	if err := os.Rename(path, renamed); err != nil {
+		t.Fatal(err)
+	}
+	return renamed
+}
+
+func TestDecodeContainerOrder(t *testing.T) {
+	errDecode := errors.New("decode failed")
+	tests := []struct {
+		name      string
+		file      string
+		fails     []string // Containers decode fails for
+		wantCalls []string
+		wantErr   bool
+	}{
+		{"extension is tried first", "audio.flac", nil, []string{"flac"}, false},
+		{"header used after extension fails", "audio.flac", []string{"flac"}, []string{"flac", "wav"}, false},
+		{"header used for unknown extension", "audio.bin", nil, []string{"wav"}, false},
+		{"matching header is not retried", "audio.wav", []string{"wav"}, []string{"wav"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := renameTestFile(t, writeTestWAV(t, 16, 1, 16000, make([]int, 16)), tt.file)
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			var calls []string
+			_, err = decodeContainer(file, path, func(container string) (struct{}, error) {
+				calls = append(calls, container)
+				if slices.Contains(tt.fails, container) {
+					file.Seek(4, io.SeekStart) // Leave the file mid-header, as a failed decoder would
+					return struct{}{}, errDecode
+				}
+				return struct{}{}, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeContainer() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("decode called with %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestLoadMisnamedAudio(t *testing.T) {
+	for _, name := range []string{"episode.mp3", "episode.ogg", "episode.audio"} {
+		t.Run(name, func(t *testing.T) {
+			path := renameTestFile(t, writeTestWAV(t, 16, 1, 16000, make([]int, 1600)), name)
+
+			samples, err := loadAudioFile(path, MixAverage, discardLogger())
+			if err != nil {
+				t.Fatalf("loadAudioFile: %v", err)
+			}
+			if len(samples) != 1600 {
+				t.Errorf("loaded %d samples, want 1600", len(samples))
+			}
+
+			info, err := ReadAudioInfo(path)
+			if err != nil {
+				t.Fatalf("ReadAudioInfo: %v", err)
+			}
+			if info.Container != containerWAV {
+				t.Errorf("container = %q, want %q", info.Container, containerWAV)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package transcriber
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// downloadClient fetches models. Its timeouts cover connecting and waiting
+// for the response headers, not the transfer itself, since a large model can
+// take a long time to arrive; cancel the context to abort a download.
+var downloadClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	},
+}
+
+// IsModelURL reports whether a model path is an http(s) URL rather than a local file
+func IsModelURL(modelPath string) bool {
+	return strings.HasPrefix(modelPath, "http://") || strings.HasPrefix(modelPath, "https://")
+}
+
+// FetchModel downloads the model at modelURL into the model cache directory
+// and returns the local path. A previously downloaded copy is reused. If
+// checksum is non-empty, it must match the file's SHA-256 hex digest.
+// Progress is logged to logger, which may be nil.
+func FetchModel(modelURL, checksum string, logger *slog.Logger) (string, error) {
+	return FetchModelContext(context.Background(), modelURL, checksum, logger)
+}
+
+// FetchModelContext is like FetchModel but abandons the download when ctx
+// is cancelled
+func FetchModelContext(ctx context.Context, modelURL, checksum string, logger *slog.Logger) (string, error) {
+	logger = orDiscard(logger)
+	localPath, err := cachedModelPath(modelURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Reuse the cached copy if present
+	if _, err := os.Stat(localPath); err == nil {
+		if checksum != "" {
+			if err := verifyChecksum(localPath, checksum); err != nil {
+				return "", fmt.Errorf("cached model %s: %w", localPath, err)
+			}
+		}
+		logger.Info("Using cached model", "path", localPath)
+		return localPath, nil
+	}
+
+	if err := downloadFile(ctx, modelURL, localPath, checksum, logger); err != nil {
+		return "", err
+	}
+	return localPath, nil
+}
+
+// cachedModelPath returns the cache location for a model URL. The file name is
+// prefixed with a hash of the URL so different URLs never share a cache entry.
+func cachedModelPath(modelURL string) (string, error) {
+	u, err := url.Parse(modelURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid model URL: %w", err)
+	}
+
+	name := path.Base(u.Path)
+	if name == "" || name == "." || name == "/" {
+		name = "model.bin"
+	}
+
+	dir := GetDefaultModelDir()
+	if dir == "" {
+		return "", fmt.Errorf("could not determine model cache directory")
+	}
+
+	sum := sha256.Sum256([]byte(modelURL))
+	return filepath.Join(dir, fmt.Sprintf("url-%s-%s", hex.EncodeToString(sum[:4]), name)), nil
+}
+
+// downloadFile streams url to a temporary file next to dest, verifies the
+// checksum if one is given, and renames it into place. On any failure the
+// temporary file is removed so no partial or corrupt file is left at dest,
+// including when ctx is cancelled mid-transfer.
+func downloadFile(ctx context.Context, fileURL, dest, checksum string, logger *slog.Logger) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create model directory: %w", err)
+	}
+
+	logger.Info("Downloading", "url", fileURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", fileURL, resp.Status)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".*.part")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // No-op once renamed into place
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmpFile, hasher), resp.Body)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	if resp.ContentLength > 0 && written != resp.ContentLength {
+		return fmt.Errorf("incomplete download of %s: got %d of %d bytes", fileURL, written, resp.ContentLength)
+	}
+
+	if checksum != "" {
+		if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, checksum) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", fileURL, checksum, actual)
+		}
+	}
+
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+
+	logger.Info("Downloaded", "size_mb", written>>20, "path", dest)
+	return nil
+}
+
+// verifyChecksum compares a file's SHA-256 hex digest to the expected value
+func verifyChecksum(filePath, checksum string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, checksum) {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+	return nil
+}
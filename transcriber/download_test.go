@@ -0,0 +1,83 @@
+package transcriber
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadFileChecksum(t *testing.T) {
+	body := []byte("not really a model")
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		{"matching checksum", digest, false},
+		{"matching checksum in upper case", strings.ToUpper(digest), false},
+		{"mismatched checksum", "00" + digest[2:], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+			err := downloadFile(context.Background(), server.URL, dest, tt.checksum, discardLogger())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("downloadFile() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			_, statErr := os.Stat(dest)
+			if tt.wantErr && statErr == nil {
+				t.Error("rejected download was left at dest")
+			}
+			if !tt.wantErr && statErr != nil {
+				t.Errorf("verified download missing: %v", statErr)
+			}
+			entries, _ := os.ReadDir(filepath.Dir(dest))
+			for _, e := range entries {
+				if filepath.Ext(e.Name()) == ".part" {
+					t.Errorf("temporary file %s left behind", e.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadFileCancelled(t *testing.T) {
+	// Send part of the body, then stall until the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		w.Write(make([]byte, 512))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	dest := filepath.Join(t.TempDir(), "ggml-test.bin")
+	err := downloadFile(ctx, server.URL, dest, "", discardLogger())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("downloadFile() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(dest))
+	for _, e := range entries {
+		t.Errorf("file %s left behind after cancelled download", e.Name())
+	}
+}
@@ -0,0 +1,45 @@
+package transcriber
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/mewkiz/flac"
+)
+
+// loadFLAC decodes a FLAC file and converts it to the format required by Whisper
+// Decoded samples are converted the same way as WAV data.
+func loadFLAC(r io.Reader, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	stream, err := flac.New(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FLAC file: %w", err)
+	}
+	defer stream.Close()
+
+	numChannels := int(stream.Info.NChannels)
+	sampleRate := int(stream.Info.SampleRate)
+	bitDepth := int(stream.Info.BitsPerSample)
+
+	logAudioFormat(logger, "FLAC", sampleRate, bitDepth, numChannels)
+
+	// Interleave the per-channel subframes to match the WAV sample layout
+	samples := make([]int, 0, int(stream.Info.NSamples)*numChannels)
+	for {
+		frame, err := stream.ParseNext()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode FLAC data: %w", err)
+		}
+
+		for i := 0; i < frame.Subframes[0].NSamples; i++ {
+			for _, subframe := range frame.Subframes {
+				samples = append(samples, int(subframe.Samples[i]))
+			}
+		}
+	}
+
+	return convertSamples(samples, numChannels, sampleRate, bitDepth, mix, logger)
+}
@@ -0,0 +1,27 @@
+package transcriber
+
+import (
+	"log/slog"
+	"math"
+)
+
+// logger returns the logger to report progress to: config.Logger when set,
+// otherwise a logger that discards everything
+func (c WhisperConfig) logger() *slog.Logger {
+	return orDiscard(c.Logger)
+}
+
+// orDiscard returns logger, or a logger that discards every record if it
+// is nil
+func orDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return logger
+}
+
+// roundForLog rounds x to two decimal places so logged durations and levels
+// stay readable
+func roundForLog(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,77 @@
+package transcriber
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Approximate memory used by one transcriber instance for each model size,
+// from the whisper.cpp model table plus working buffers. Quantized and
+// English-only variants use the estimate for their base size.
+const (
+	TinyModelBytes   uint64 = 400 << 20
+	BaseModelBytes   uint64 = 500 << 20
+	SmallModelBytes  uint64 = 1 << 30
+	MediumModelBytes uint64 = 2600 << 20
+	LargeModelBytes  uint64 = 3 << 30
+)
+
+// EstimateInstanceMemory returns the approximate memory in bytes one
+// transcriber instance needs for the model at modelPath, based on the model
+// size in its file name (e.g., "ggml-medium.en.bin"). Unrecognized models are
+// assumed to be large.
+func EstimateInstanceMemory(modelPath string) uint64 {
+	name := strings.ToLower(filepath.Base(modelPath))
+	switch {
+	case strings.Contains(name, "tiny"):
+		return TinyModelBytes
+	case strings.Contains(name, "base"):
+		return BaseModelBytes
+	case strings.Contains(name, "small"):
+		return SmallModelBytes
+	case strings.Contains(name, "medium"):
+		return MediumModelBytes
+	default:
+		return LargeModelBytes
+	}
+}
+
+// availableMemory returns the memory in bytes available to new processes
+// without swapping, as reported by MemAvailable in /proc/meminfo. The boolean
+// is false where that is not available, such as on macOS.
+func availableMemory() (uint64, bool) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Format: "MemAvailable:   12345678 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return kb << 10, true
+	}
+	return 0, false
+}
+
+// MaxTranscribersForMemory returns how many transcriber instances for the
+// model at modelPath fit in available memory, at least 1. The boolean is
+// false when available memory cannot be determined.
+func MaxTranscribersForMemory(modelPath string) (int, bool) {
+	available, ok := availableMemory()
+	if !ok {
+		return 0, false
+	}
+	return max(int(available/EstimateInstanceMemory(modelPath)), 1), true
+}
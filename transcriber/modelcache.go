@@ -0,0 +1,133 @@
+package transcriber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ModelBaseURL is where the standard whisper.cpp models are published
+const ModelBaseURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main"
+
+// KnownModels lists the names of the standard multilingual whisper.cpp
+// models, smallest first
+var KnownModels = []string{"tiny", "base", "small", "medium", "large-v2", "large-v3"}
+
+// ModelDownloadURL returns the download URL of a standard model by name
+func ModelDownloadURL(modelName string) string {
+	return fmt.Sprintf("%s/ggml-%s.bin", ModelBaseURL, modelName)
+}
+
+// checksumLookupTimeout bounds the request for a published checksum
+const checksumLookupTimeout = 30 * time.Second
+
+// DownloadModel downloads a standard model by name to dest. The download is
+// verified against checksum if given, otherwise against the SHA-256 digest
+// Hugging Face publishes for the file. That digest is served by the same
+// host as the model, so it catches corrupt or truncated transfers but not a
+// tampered file; pass a checksum from a trusted source for that, since no
+// digests ship with the tool. Like FetchModel, it streams
+// to a temporary file and only renames it into place once verified, so a
+// failed or interrupted download never leaves a corrupt model at dest.
+// Progress is logged to logger, which may be nil.
+func DownloadModel(modelName, dest, checksum string, logger *slog.Logger) error {
+	return DownloadModelContext(context.Background(), modelName, dest, checksum, logger)
+}
+
+// DownloadModelContext is like DownloadModel but abandons the download when
+// ctx is cancelled
+func DownloadModelContext(ctx context.Context, modelName, dest, checksum string, logger *slog.Logger) error {
+	logger = orDiscard(logger)
+	modelURL := ModelDownloadURL(modelName)
+	if checksum == "" {
+		published, err := publishedChecksum(ctx, modelURL)
+		if err != nil {
+			return err
+		}
+		logger.Warn("No checksum given; verifying against the digest published with the download, which only shows the transfer completed", "model", modelName)
+		checksum = published
+	}
+	logger.Info("Expected SHA-256", "checksum", checksum)
+	return downloadFile(ctx, modelURL, dest, checksum, logger)
+}
+
+// publishedChecksum returns the SHA-256 digest of a file stored with Git LFS
+// on Hugging Face. The resolve endpoint reports it in the X-Linked-Etag
+// header of its redirect to the storage backend, so the redirect is not
+// followed.
+func publishedChecksum(ctx context.Context, fileURL string) (string, error) {
+	client := &http.Client{
+		Transport: downloadClient.Transport,
+		Timeout:   checksumLookupTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fileURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up checksum for %s: %w", fileURL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up checksum for %s: %w", fileURL, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("no model published at %s", fileURL)
+	}
+	checksum := strings.Trim(resp.Header.Get("X-Linked-Etag"), `"`)
+	if len(checksum) != 64 {
+		return "", fmt.Errorf("no SHA-256 checksum published for %s; pass one with --model-sha256", fileURL)
+	}
+	return checksum, nil
+}
+
+// ModelFile describes a model file in the model cache directory
+type ModelFile struct {
+	Name string // Model name, as accepted by --model (e.g., "base.en")
+	Path string // Full path to the file
+	Size int64  // File size in bytes
+}
+
+// ListModels returns the ggml-*.bin model files in the default model
+// directory, sorted by file name. A missing directory has no models.
+func ListModels() ([]ModelFile, error) {
+	dir := GetDefaultModelDir()
+	if dir == "" {
+		return nil, fmt.Errorf("could not determine model directory")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model directory: %w", err)
+	}
+
+	var models []ModelFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "ggml-") || !strings.HasSuffix(name, ".bin") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model file: %w", err)
+		}
+		models = append(models, ModelFile{
+			Name: ModelName(name),
+			Path: filepath.Join(dir, name),
+			Size: info.Size(),
+		})
+	}
+	return models, nil
+}
@@ -0,0 +1,39 @@
+package transcriber
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// loadMP3 decodes an MP3 file and converts it to the format required by Whisper
+// The decoder always produces 16-bit little-endian stereo PCM, which is then
+// converted the same way as WAV data.
+func loadMP3(r io.Reader, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	decoder, err := mp3.NewDecoder(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MP3 file: %w", err)
+	}
+
+	const (
+		numChannels = 2
+		bitDepth    = 16
+	)
+
+	logAudioFormat(logger, "MP3", decoder.SampleRate(), bitDepth, numChannels)
+
+	data, err := io.ReadAll(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode MP3 data: %w", err)
+	}
+
+	samples := make([]int, len(data)/2)
+	for i := range samples {
+		samples[i] = int(int16(binary.LittleEndian.Uint16(data[i*2:])))
+	}
+
+	return convertSamples(samples, numChannels, decoder.SampleRate(), bitDepth, mix, logger)
+}
@@ -0,0 +1,50 @@
+package transcriber
+
+import (
+	"math"
+)
+
+const (
+	// normalizeTarget is the peak magnitude audio is scaled to
+	normalizeTarget = 0.95
+
+	// normalizeMinPeak is the peak above which audio is already near full
+	// scale and is left unchanged
+	normalizeMinPeak = 0.9
+
+	// normalizeSilencePeak is the peak below which audio is effectively
+	// silent (-60 dBFS); amplifying it would only raise the noise floor
+	normalizeSilencePeak = 0.001
+)
+
+// normalizePeak scales samples in place so their peak magnitude is
+// normalizeTarget, and returns the gain applied. Audio already near full
+// scale or effectively silent is left unchanged with a gain of 1.
+func normalizePeak(samples []float32) float32 {
+	var peak float32
+	for _, sample := range samples {
+		peak = max(peak, sample, -sample)
+	}
+	if peak >= normalizeMinPeak || peak < normalizeSilencePeak {
+		return 1
+	}
+
+	gain := normalizeTarget / peak
+	for i := range samples {
+		samples[i] *= gain
+	}
+	return gain
+}
+
+// normalizeAudio applies normalizePeak when config.Normalize is set,
+// logging the gain applied
+func normalizeAudio(samples []float32, config WhisperConfig) {
+	if !config.Normalize {
+		return
+	}
+	gain := normalizePeak(samples)
+	if gain != 1 {
+		config.logger().Debug("Normalized peak", "peak", normalizeTarget,
+			"gain_db", roundForLog(20*math.Log10(float64(gain))))
+	}
+}
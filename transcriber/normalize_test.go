@@ -0,0 +1,58 @@
+package transcriber
+
+import (
+	"math"
+	"testing"
+)
+
+// sine returns n samples of a 440Hz sine at 16kHz with the given peak
+func sine(n int, peak float64) []float32 {
+	samples := make([]float32, n)
+	for i := range samples {
+		samples[i] = float32(peak * math.Sin(2*math.Pi*440*float64(i)/16000))
+	}
+	return samples
+}
+
+// peakOf returns the largest magnitude in samples
+func peakOf(samples []float32) float64 {
+	var peak float64
+	for _, s := range samples {
+		peak = max(peak, math.Abs(float64(s)))
+	}
+	return peak
+}
+
+func TestNormalizePeak(t *testing.T) {
+	tests := []struct {
+		name     string
+		peak     float64 // Peak of the input sine
+		wantPeak float64 // Peak after normalizing
+		wantGain float64
+	}{
+		{"-20dB sine brought to target", 0.1, normalizeTarget, normalizeTarget / 0.1},
+		{"quiet sine brought to target", 0.002, normalizeTarget, normalizeTarget / 0.002},
+		{"near full scale untouched", 0.92, 0.92, 1},
+		{"full scale untouched", 1, 1, 1},
+		{"effectively silent untouched", 0.0005, 0.0005, 1},
+		{"digital silence untouched", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := sine(16000, tt.peak)
+			inputPeak := peakOf(samples)
+
+			gain := normalizePeak(samples)
+			if math.Abs(float64(gain)-tt.wantGain) > 1e-3*tt.wantGain {
+				t.Errorf("gain = %g, want %g", gain, tt.wantGain)
+			}
+			wantPeak := tt.wantPeak
+			if tt.wantGain == 1 {
+				wantPeak = inputPeak // Exactly unchanged
+			}
+			if got := peakOf(samples); math.Abs(got-wantPeak) > 1e-6 {
+				t.Errorf("peak = %g, want %g", got, wantPeak)
+			}
+		})
+	}
+}
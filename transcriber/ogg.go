@@ -0,0 +1,144 @@
+package transcriber
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/jfreymuth/oggvorbis"
+	"gopkg.in/hraban/opus.v2"
+)
+
+// Codecs carried in Ogg containers, identified by readOggHeader
+const (
+	codecVorbis = "vorbis"
+	codecOpus   = "opus"
+)
+
+const (
+	// oggPageHeaderSize is the size of an Ogg page header before its segment table
+	oggPageHeaderSize = 27
+
+	// opusSampleRate is the rate Opus always decodes at, whatever the input rate
+	opusSampleRate = 48000
+
+	// opusMaxFrameSamples is the per-channel sample count of the longest
+	// (120ms) Opus frame at opusSampleRate
+	opusMaxFrameSamples = 5760
+
+	// vorbisBitDepth is the integer bit depth decoded Vorbis samples are
+	// scaled to so they can share the integer conversion path
+	vorbisBitDepth = 24
+)
+
+// oggHeader holds the codec and format from the identification header in
+// the first page of an Ogg stream
+type oggHeader struct {
+	codec      string
+	channels   int
+	sampleRate int // Rate of the decoded audio
+	inputRate  int // Rate of the original audio, if recorded (Opus only)
+	preSkip    int // Decoded samples to discard from the start (Opus only)
+}
+
+// readOggHeader parses the identification header from the first page of an
+// Ogg stream without consuming it
+func readOggHeader(br *bufio.Reader) (oggHeader, error) {
+	page, err := br.Peek(oggPageHeaderSize)
+	if err != nil || !bytes.HasPrefix(page, []byte("OggS")) {
+		return oggHeader{}, fmt.Errorf("invalid Ogg file: missing page header")
+	}
+
+	// The first packet follows the segment table
+	packetStart := oggPageHeaderSize + int(page[26])
+	page, err = br.Peek(packetStart + 19)
+	if err != nil {
+		return oggHeader{}, fmt.Errorf("invalid Ogg file: truncated identification header")
+	}
+	packet := page[packetStart:]
+
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		return oggHeader{
+			codec:      codecVorbis,
+			channels:   int(packet[11]),
+			sampleRate: int(binary.LittleEndian.Uint32(packet[12:16])),
+		}, nil
+	case bytes.HasPrefix(packet, []byte("OpusHead")):
+		return oggHeader{
+			codec:      codecOpus,
+			channels:   int(packet[9]),
+			sampleRate: opusSampleRate,
+			inputRate:  int(binary.LittleEndian.Uint32(packet[12:16])),
+			preSkip:    int(binary.LittleEndian.Uint16(packet[10:12])),
+		}, nil
+	}
+	return oggHeader{}, fmt.Errorf("unsupported Ogg codec: only Vorbis and Opus are supported")
+}
+
+// loadOgg decodes an Ogg Vorbis or Ogg Opus file and converts it to the
+// format required by Whisper. The codec is identified from the stream's
+// first page, and decoded samples are converted the same way as WAV data.
+func loadOgg(r io.Reader, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	br := bufio.NewReader(r)
+	header, err := readOggHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.codec == codecOpus {
+		return loadOpus(br, header, mix, logger)
+	}
+	return loadVorbis(br, mix, logger)
+}
+
+// loadVorbis decodes an Ogg Vorbis stream. The decoder produces float
+// samples, which are scaled to vorbisBitDepth integers for conversion.
+func loadVorbis(r io.Reader, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	data, format, err := oggvorbis.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Vorbis data: %w", err)
+	}
+
+	logAudioFormat(logger, "Ogg Vorbis", format.SampleRate, 0, format.Channels)
+
+	scale := normalizationFactor(vorbisBitDepth)
+	samples := make([]int, len(data))
+	for i, sample := range data {
+		samples[i] = int(sample * scale)
+	}
+
+	return convertSamples(samples, format.Channels, format.SampleRate, vorbisBitDepth, mix, logger)
+}
+
+// loadOpus decodes an Ogg Opus stream. Opus always decodes to 16-bit
+// samples at opusSampleRate, interleaved across the channels in the header.
+func loadOpus(r io.Reader, header oggHeader, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	stream, err := opus.NewStream(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Opus file: %w", err)
+	}
+	defer stream.Close()
+
+	logAudioFormat(logger, "Ogg Opus", header.sampleRate, 0, header.channels)
+
+	var samples []int
+	buf := make([]int16, opusMaxFrameSamples*header.channels)
+	for {
+		n, err := stream.Read(buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Opus data: %w", err)
+		}
+		for _, sample := range buf[:n*header.channels] {
+			samples = append(samples, int(sample))
+		}
+	}
+
+	return convertSamples(samples, header.channels, header.sampleRate, 16, mix, logger)
+}
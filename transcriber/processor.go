@@ -1,9 +1,16 @@
 package transcriber
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"skriptble.dev/podcast-tools/models"
 )
@@ -12,14 +19,71 @@ import (
 type AudioFile struct {
 	Path    string // Path to the audio file
 	Speaker string // Speaker label for this file
+	Role    string // Optional speaker role, e.g. "Host" or "Guest"
+
+	// Language overrides WhisperConfig.Language for this file when non-empty
+	// (e.g., "es", or "auto" to detect this file's language even when a
+	// language is set globally). When empty the global language is used.
+	Language string
+
+	// ChannelMix overrides WhisperConfig.ChannelMix for this file when
+	// non-empty, so two AudioFiles can transcribe different channels of the
+	// same file (e.g., MixLeft and MixRight of a stereo interview)
+	ChannelMix ChannelMix
 }
 
 // ProcessConfig holds configuration for parallel processing
 type ProcessConfig struct {
-	AudioFiles      []AudioFile    // Audio files to process
-	WhisperConfig   WhisperConfig  // Whisper configuration
-	MaxParallel     int            // Maximum number of parallel transcriptions (0 = number of CPUs)
-	NumTranscribers int            // Number of transcriber instances to create (0 = 1, for memory/speed tradeoff)
+	AudioFiles      []AudioFile   // Audio files to process
+	WhisperConfig   WhisperConfig // Whisper configuration
+	MaxParallel     int           // Maximum number of parallel transcriptions (0 = number of CPUs)
+	NumTranscribers int           // Number of transcriber instances to create (0 = 1, for memory/speed tradeoff)
+	AllowOvercommit bool          // Create NumTranscribers instances even if they may not fit in available memory
+	LoadConcurrency int           // Number of transcriber instances to load at once (0 = 1, sequential)
+	SuppressBleed   bool          // Drop duplicate segments picked up by another speaker's microphone
+	Transforms      []Transform   // Post-processing steps run in order after segments are sorted
+
+	// ChunkSeconds, if positive, splits each file into windows of this many
+	// seconds (plus a short overlap) that are transcribed as separate jobs,
+	// so a single long file can use every transcriber instance. Chunked
+	// files are fully decoded before transcription starts, so all of their
+	// audio is held in memory at once (about 230 MB per hour).
+	ChunkSeconds int
+
+	// MaxRetries is the number of times a failed transcription is retried,
+	// with a short exponential backoff, before the file is recorded as
+	// failed. Each retry uses a fresh whisper context; audio is not reloaded.
+	MaxRetries int
+
+	// PerFileTimeout, if positive, limits how long each file (or each chunk,
+	// with ChunkSeconds) may take to transcribe. A file that runs over is
+	// recorded as failed with an error wrapping context.DeadlineExceeded and
+	// its worker moves on. Whisper only checks for cancellation between
+	// windows of audio, so a transcription that does not stop is abandoned
+	// rather than interrupted: it keeps its transcriber instance, and that
+	// instance's memory and CPU, until whisper returns. Jobs that cannot get
+	// an instance within the timeout because every instance is held by an
+	// abandoned transcription also fail.
+	PerFileTimeout time.Duration
+
+	// ContinueOnError keeps the segments of files that transcribed when
+	// others fail. The partial transcript is returned along with a
+	// *PartialError naming the failed files. By default, any failed file
+	// fails the whole run.
+	ContinueOnError bool
+
+	// Progress, if set, is called each time a file finishes transcribing
+	// (successfully or not) with the number of files completed so far, the
+	// total number of files, and the speaker of the file that just finished.
+	// It is called from worker goroutines and may be called concurrently, so
+	// any shared state it touches must be guarded by the caller.
+	Progress func(done, total int, currentSpeaker string)
+
+	// Timings, if set, is called once all files have finished (successfully
+	// or not) with how long the run spent loading models and transcribing,
+	// for comparing models and hardware. It is not called if the run is
+	// cancelled or no transcriber instance loads.
+	Timings func(timings Timings)
 }
 
 // ProcessResult holds the result of processing a single file
@@ -27,13 +91,98 @@ type ProcessResult struct {
 	Speaker  string
 	Segments []models.Segment
 	Error    error
+	Language string // Language whisper transcribed in, detected when set to "auto"
+	// WallTime is the time spent decoding and transcribing the file, summed
+	// over its chunks when ChunkSeconds is set
+	WallTime time.Duration
+	// AudioSeconds is the end time of the file's last segment, which
+	// approximates the length of the audio transcribed, less any trailing
+	// silence
+	AudioSeconds float64
+
+	energy []float32 // Audio energy envelope, collected for bleed suppression
+	file   int       // Index of the file in ProcessConfig.AudioFiles
+}
+
+// RealTimeFactor returns the seconds of audio transcribed per second of
+// WallTime, or 0 if no time was recorded. Values above 1 are faster than
+// real time.
+func (r ProcessResult) RealTimeFactor() float64 {
+	return realTimeFactor(r.AudioSeconds, r.WallTime)
+}
+
+// Timings reports how long a run took, as passed to ProcessConfig.Timings
+type Timings struct {
+	// ModelLoad is the longest time taken to load one transcriber instance
+	ModelLoad time.Duration
+	// Transcribers is the number of instances loaded, which may be fewer
+	// than requested to fit in available memory
+	Transcribers int
+	// Transcribe is the wall-clock time from after the models loaded until
+	// every file finished, with files transcribed in parallel
+	Transcribe time.Duration
+	// Files holds each file's result in ProcessConfig.AudioFiles order
+	Files []ProcessResult
+}
+
+// AudioSeconds returns the total audio transcribed by files that succeeded
+func (t Timings) AudioSeconds() float64 {
+	var total float64
+	for _, f := range t.Files {
+		if f.Error == nil {
+			total += f.AudioSeconds
+		}
+	}
+	return total
+}
+
+// RealTimeFactor returns the seconds of audio transcribed per second of
+// Transcribe time across all files, including the speedup from
+// transcribing them in parallel
+func (t Timings) RealTimeFactor() float64 {
+	return realTimeFactor(t.AudioSeconds(), t.Transcribe)
+}
+
+// realTimeFactor returns audioSeconds per second of elapsed, or 0 if no time
+// elapsed
+func realTimeFactor(audioSeconds float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return audioSeconds / elapsed.Seconds()
 }
 
-// ProcessFiles transcribes multiple audio files in parallel
+// job is a unit of work for a worker: a whole file, or one chunk of it
+type job struct {
+	file  AudioFile
+	index int         // Index of the file in ProcessConfig.AudioFiles
+	chunk *audioChunk // Chunk to transcribe, or nil for the whole file
+}
+
+// ProcessFiles transcribes multiple audio files in parallel. If any file
+// fails, the run fails unless config.ContinueOnError is set, in which case
+// the transcript of the other files is returned with a *PartialError.
 func ProcessFiles(config ProcessConfig) (*models.Transcript, error) {
+	return ProcessFilesContext(context.Background(), config)
+}
+
+// ProcessFilesContext is like ProcessFiles but stops early when ctx is
+// cancelled. Files not yet started are skipped and files in progress stop at
+// their next segment boundary, after which an error wrapping ctx.Err() (e.g.,
+// context.Canceled) is returned. All transcriber instances are closed before
+// it returns, except any still held by a transcription abandoned after
+// PerFileTimeout, which are closed when that transcription returns.
+func ProcessFilesContext(ctx context.Context, config ProcessConfig) (*models.Transcript, error) {
 	if len(config.AudioFiles) == 0 {
 		return nil, fmt.Errorf("no audio files provided")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("transcription cancelled: %w", err)
+	}
+
+	// Resolve the logger once so every transcriber instance shares it
+	logger := config.WhisperConfig.logger()
+	config.WhisperConfig.Logger = logger
 
 	// Determine number of transcriber instances
 	numTranscribers := config.NumTranscribers
@@ -41,6 +190,16 @@ func ProcessFiles(config ProcessConfig) (*models.Transcript, error) {
 		numTranscribers = 1
 	}
 
+	// Reduce the instance count rather than running out of memory mid-run
+	if !config.AllowOvercommit {
+		if limit, ok := MaxTranscribersForMemory(config.WhisperConfig.ModelPath); ok && numTranscribers > limit {
+			logger.Warn("Not enough available memory for all transcriber instances",
+				"requested", numTranscribers, "needed_mb", uint64(numTranscribers)*EstimateInstanceMemory(config.WhisperConfig.ModelPath)>>20,
+				"using", limit)
+			numTranscribers = limit
+		}
+	}
+
 	// Determine parallelism (number of workers)
 	maxParallel := config.MaxParallel
 	if maxParallel <= 0 {
@@ -51,120 +210,450 @@ func ProcessFiles(config ProcessConfig) (*models.Transcript, error) {
 		maxParallel = numTranscribers
 	}
 
-	if config.WhisperConfig.Verbose {
-		fmt.Printf("Processing %d audio files with %d transcriber instance(s) and %d parallel worker(s)\n",
-			len(config.AudioFiles), numTranscribers, maxParallel)
-	}
+	logger.Info("Processing audio files", "files", len(config.AudioFiles),
+		"transcribers", numTranscribers, "workers", maxParallel)
 
 	// Create a pool of transcriber instances
-	transcriberPool := make(chan *WhisperTranscriber, numTranscribers)
-	var transcribers []*WhisperTranscriber
+	transcribers, modelLoad, err := loadTranscribers(config.WhisperConfig, numTranscribers, config.LoadConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	pool := &instancePool{instances: make(chan *WhisperTranscriber, numTranscribers)}
+	for _, t := range transcribers {
+		pool.put(t)
+	}
+	defer pool.close()
 
-	for i := 0; i < numTranscribers; i++ {
-		transcriber, err := NewWhisperTranscriber(config.WhisperConfig)
+	// Split files into chunks when requested. Chunked files are loaded here
+	// so their length is known, and load failures are reported as results.
+	var jobList []job
+	var loadFailures []ProcessResult
+	envelopes := make(map[string][]float32)
+	for i, audioFile := range config.AudioFiles {
+		if config.ChunkSeconds <= 0 {
+			jobList = append(jobList, job{file: audioFile, index: i})
+			continue
+		}
+
+		audio, err := readInput(audioFile, config.WhisperConfig, logger)
 		if err != nil {
-			// Clean up any transcribers already created
-			for _, t := range transcribers {
-				t.Close()
-			}
-			return nil, fmt.Errorf("failed to create transcriber %d: %w", i+1, err)
+			loadFailures = append(loadFailures, ProcessResult{Speaker: audioFile.Speaker, Error: err, file: i})
+			continue
 		}
-		transcribers = append(transcribers, transcriber)
-		transcriberPool <- transcriber
-	}
-	defer func() {
-		// Clean up all transcribers
-		for _, t := range transcribers {
-			t.Close()
+		if config.SuppressBleed {
+			envelopes[audioFile.Speaker] = energyEnvelope(audio)
 		}
-	}()
+		normalizeAudio(audio, config.WhisperConfig)
+		audio, lead := trimSilence(audio, config.WhisperConfig)
+		for _, chunk := range splitChunks(audio, config.ChunkSeconds, lead) {
+			jobList = append(jobList, job{file: audioFile, index: i, chunk: &chunk})
+		}
+	}
 
 	// Create channels for work distribution
-	jobs := make(chan AudioFile, len(config.AudioFiles))
-	results := make(chan ProcessResult, len(config.AudioFiles))
+	jobs := make(chan job, len(jobList))
+	results := make(chan ProcessResult, len(jobList)+len(loadFailures))
+
+	// Track outstanding jobs per file so progress is reported per file
+	var completed atomic.Int64
+	remaining := make([]atomic.Int64, len(config.AudioFiles))
+	for _, j := range jobList {
+		remaining[j.index].Add(1)
+	}
+	for _, failure := range loadFailures {
+		remaining[failure.file].Add(1)
+	}
+	fileDone := func(index int) {
+		if remaining[index].Add(-1) > 0 {
+			return
+		}
+		done := completed.Add(1)
+		if config.Progress != nil {
+			config.Progress(int(done), len(config.AudioFiles), config.AudioFiles[index].Speaker)
+		}
+	}
+
+	for _, failure := range loadFailures {
+		results <- failure
+		fileDone(failure.file)
+	}
 
 	// Create worker pool
 	var wg sync.WaitGroup
 	for i := 0; i < maxParallel; i++ {
 		wg.Add(1)
-		go workerWithPool(i, transcriberPool, jobs, results, &wg)
+		go workerWithPool(ctx, i, config, pool, jobs, results, fileDone, &wg)
 	}
 
 	// Send jobs to workers
-	for _, audioFile := range config.AudioFiles {
-		jobs <- audioFile
+	for _, j := range jobList {
+		jobs <- j
 	}
 	close(jobs)
 
 	// Wait for all workers to finish
 	wg.Wait()
 	close(results)
+	elapsed := time.Since(start)
+
+	// Discard partial results when cancelled
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("transcription cancelled: %w", err)
+	}
+
+	// Gather each file's results; a file fails if any of its chunks failed
+	fileResults := make([]ProcessResult, len(config.AudioFiles))
+	for result := range results {
+		fileResult := &fileResults[result.file]
+		fileResult.Speaker = result.Speaker
+		fileResult.Segments = append(fileResult.Segments, result.Segments...)
+		if fileResult.Error == nil {
+			fileResult.Error = result.Error
+		}
+		if result.energy != nil {
+			fileResult.energy = result.energy
+		}
+		if result.Language != "" {
+			fileResult.Language = result.Language
+		}
+		fileResult.WallTime += result.WallTime
+		fileResult.AudioSeconds = max(fileResult.AudioSeconds, result.AudioSeconds)
+	}
+	if config.Timings != nil {
+		config.Timings(Timings{
+			ModelLoad:    modelLoad,
+			Transcribers: numTranscribers,
+			Transcribe:   elapsed,
+			Files:        fileResults,
+		})
+	}
 
 	// Collect results
 	transcript := models.NewTranscript()
 	var processingErrors []error
 
-	for result := range results {
+	for _, result := range fileResults {
 		if result.Error != nil {
 			processingErrors = append(processingErrors,
 				fmt.Errorf("error processing %s: %w", result.Speaker, result.Error))
 			continue
 		}
 
+		if config.ChunkSeconds > 0 {
+			sort.SliceStable(result.Segments, func(i, j int) bool {
+				return result.Segments[i].StartTime < result.Segments[j].StartTime
+			})
+			renumberSourceIDs(result.Segments)
+		} else {
+			envelopes[result.Speaker] = result.energy
+		}
 		transcript.AddSegments(result.Segments)
+
+		if result.Language != "" {
+			transcript.SetDetectedLanguage(result.Speaker, result.Language)
+			logger.Info("Detected language", "speaker", result.Speaker, "language", result.Language)
+		}
 	}
 
+	// Record what produced the transcript for output metadata
+	transcript.Model = ModelName(config.WhisperConfig.ModelPath)
+	transcript.Language = transcriptLanguage(config.WhisperConfig, transcript.DetectedLanguages)
+
 	// Check if any files were processed successfully
 	if len(transcript.Segments) == 0 {
 		if len(processingErrors) > 0 {
-			return nil, fmt.Errorf("all transcriptions failed: %v", processingErrors)
+			return nil, fmt.Errorf("all transcriptions failed: %w", errors.Join(processingErrors...))
 		}
 		return nil, fmt.Errorf("no segments produced from transcription")
 	}
+	if len(processingErrors) > 0 && !config.ContinueOnError {
+		return nil, fmt.Errorf("transcription failed: %w", errors.Join(processingErrors...))
+	}
 
 	// Sort segments by time
 	transcript.SortByTime()
 
-	if config.WhisperConfig.Verbose {
-		fmt.Printf("Transcription complete: %d total segments from %d speakers\n",
-			len(transcript.Segments), len(config.AudioFiles))
+	// Remove speech picked up by the wrong microphone
+	if config.SuppressBleed {
+		removed := suppressBleed(transcript, envelopes)
+		logger.Info("Removed bleed segments", "segments", removed)
+	}
 
-		if len(processingErrors) > 0 {
-			fmt.Printf("Warning: %d files failed to process\n", len(processingErrors))
-			for _, err := range processingErrors {
-				fmt.Printf("  - %v\n", err)
-			}
+	// Record speaker metadata
+	for _, af := range config.AudioFiles {
+		if af.Role != "" {
+			transcript.SetSpeakerRole(af.Speaker, af.Role)
+		}
+	}
+
+	// Apply post-processing
+	if len(config.Transforms) > 0 {
+		logger.Info("Applying transforms", "transforms", len(config.Transforms))
+		if err := ApplyTransforms(transcript, config.Transforms); err != nil {
+			return nil, err
 		}
 	}
 
+	logger.Info("Transcription complete", "segments", len(transcript.Segments),
+		"speakers", len(config.AudioFiles)-len(processingErrors))
+
+	if len(processingErrors) > 0 {
+		return transcript, &PartialError{Errors: processingErrors}
+	}
 	return transcript, nil
 }
 
-// workerWithPool processes audio files from the jobs channel using transcribers from the pool
-func workerWithPool(id int, transcriberPool chan *WhisperTranscriber, jobs <-chan AudioFile, results chan<- ProcessResult, wg *sync.WaitGroup) {
+// loadTranscribers creates count transcriber instances, loading at most
+// concurrency models at a time to bound the memory spike during startup.
+// If any instance fails to load, all loaded instances are closed. The
+// longest time taken to load one instance is also returned.
+func loadTranscribers(config WhisperConfig, count, concurrency int) ([]*WhisperTranscriber, time.Duration, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	transcribers := make([]*WhisperTranscriber, count)
+	errs := make([]error, count)
+	sem := make(chan struct{}, concurrency)
+
+	var mu sync.Mutex
+	loaded := 0
+	var slowest time.Duration
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			transcribers[i], errs[i] = NewWhisperTranscriber(config)
+			if errs[i] == nil {
+				mu.Lock()
+				loaded++
+				slowest = max(slowest, time.Since(start))
+				config.logger().Info("Loaded transcriber instance", "loaded", loaded, "count", count)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			// Clean up any transcribers already created
+			for _, t := range transcribers {
+				if t != nil {
+					t.Close()
+				}
+			}
+			return nil, 0, fmt.Errorf("failed to create transcriber %d: %w", i+1, err)
+		}
+	}
+	return transcribers, slowest, nil
+}
+
+// instancePool hands transcriber instances to workers. An instance held by
+// an abandoned transcription when the pool is closed is closed once that
+// transcription returns, never while whisper is still using it.
+type instancePool struct {
+	instances chan *WhisperTranscriber
+	mu        sync.Mutex
+	closed    bool
+}
+
+// put returns an instance to the pool, or closes it if the pool is closed
+func (p *instancePool) put(t *WhisperTranscriber) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		t.Close()
+		return
+	}
+	p.instances <- t
+}
+
+// close closes every instance in the pool and any returned to it later
+func (p *instancePool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for {
+		select {
+		case t := <-p.instances:
+			t.Close()
+		default:
+			return
+		}
+	}
+}
+
+// workerWithPool processes files and chunks from the jobs channel using
+// transcribers from the pool, calling fileDone with the file index after each
+// job. Once ctx is cancelled, remaining jobs are drained without being
+// transcribed.
+func workerWithPool(ctx context.Context, id int, config ProcessConfig, pool *instancePool, jobs <-chan job, results chan<- ProcessResult, fileDone func(index int), wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for audioFile := range jobs {
+	for j := range jobs {
+		if ctx.Err() != nil {
+			continue
+		}
+
+		jobCtx, cancel := ctx, context.CancelFunc(func() {})
+		if config.PerFileTimeout > 0 {
+			jobCtx, cancel = context.WithTimeout(ctx, config.PerFileTimeout)
+		}
+
 		// Get a transcriber from the pool
-		transcriber := <-transcriberPool
+		var transcriber *WhisperTranscriber
+		select {
+		case transcriber = <-pool.instances:
+		case <-jobCtx.Done():
+		}
 
-		// Process the file
-		segments, err := transcriber.TranscribeFile(audioFile.Path, audioFile.Speaker)
+		var result ProcessResult
+		switch {
+		case ctx.Err() != nil:
+			// Cancelled; an instance acquired just before is not used
+			if transcriber != nil {
+				pool.put(transcriber)
+			}
+			cancel()
+			continue
+		case transcriber == nil:
+			result = ProcessResult{Speaker: j.file.Speaker, Error: timeoutError(config.PerFileTimeout), file: j.index}
+		case config.PerFileTimeout > 0:
+			result = runJobWithTimeout(jobCtx, config, pool, transcriber, j)
+		default:
+			result = runJob(jobCtx, config, transcriber, j)
+			pool.put(transcriber)
+		}
+		cancel()
 
-		// Return transcriber to the pool
-		transcriberPool <- transcriber
+		// Report progress
+		fileDone(j.index)
 
 		// Send result
-		results <- ProcessResult{
-			Speaker:  audioFile.Speaker,
-			Segments: segments,
-			Error:    err,
+		results <- result
+	}
+}
+
+// runJob transcribes a file or chunk with transcriber
+func runJob(ctx context.Context, config ProcessConfig, transcriber *WhisperTranscriber, j job) ProcessResult {
+	start := time.Now()
+	result := ProcessResult{Speaker: j.file.Speaker, file: j.index}
+	if j.chunk != nil {
+		result.Segments, result.Language, result.Error = transcriber.transcribeChunk(ctx, j.file, *j.chunk, config.MaxRetries)
+		// A file's language is the one detected in its first chunk
+		if j.chunk.index > 0 {
+			result.Language = ""
 		}
+	} else {
+		result.Segments, result.energy, result.Language, result.Error = transcriber.transcribeFile(ctx, j.file, config.SuppressBleed, config.MaxRetries)
+	}
+	result.WallTime = time.Since(start)
+	for _, seg := range result.Segments {
+		result.AudioSeconds = max(result.AudioSeconds, seg.EndTime)
+	}
+	return result
+}
+
+// runJobWithTimeout runs a job in its own goroutine and returns a timeout
+// result as soon as ctx expires, whether or not whisper has stopped. The
+// goroutine returns transcriber to the pool whenever it finishes.
+func runJobWithTimeout(ctx context.Context, config ProcessConfig, pool *instancePool, transcriber *WhisperTranscriber, j job) ProcessResult {
+	done := make(chan ProcessResult, 1)
+	go func() {
+		result := runJob(ctx, config, transcriber, j)
+		pool.put(transcriber)
+		done <- result
+	}()
+
+	var result ProcessResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = ProcessResult{Speaker: j.file.Speaker, Error: ctx.Err(), file: j.index}
+	}
+	if errors.Is(result.Error, context.DeadlineExceeded) {
+		result = ProcessResult{Speaker: j.file.Speaker, Error: timeoutError(config.PerFileTimeout), file: j.index}
 	}
+	return result
+}
+
+// timeoutError reports a job that exceeded ProcessConfig.PerFileTimeout
+func timeoutError(timeout time.Duration) error {
+	return fmt.Errorf("transcription timed out after %v: %w", timeout, context.DeadlineExceeded)
 }
 
-// ValidateAudioFiles checks if all audio files exist and are accessible
+// transcriptLanguage returns the language of the transcript text: English
+// when translating, the configured language when one was set, or the
+// detected language when every speaker was detected in the same one
+func transcriptLanguage(config WhisperConfig, detected map[string]string) string {
+	if config.Translate {
+		return "en"
+	}
+	if config.Language != "" && config.Language != "auto" {
+		return config.Language
+	}
+
+	language := ""
+	for _, lang := range detected {
+		if language != "" && lang != language {
+			return ""
+		}
+		language = lang
+	}
+	return language
+}
+
+// PartialError reports files that failed to transcribe when
+// ProcessConfig.ContinueOnError is set. ProcessFiles returns it alongside the
+// transcript of the files that succeeded.
+type PartialError struct {
+	Errors []error // One error per failed file, naming its speaker
+}
+
+func (e *PartialError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d file(s) failed to transcribe: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed files
+func (e *PartialError) Unwrap() []error {
+	return e.Errors
+}
+
+// DuplicateError reports audio files that share a path or speaker label
+type DuplicateError struct {
+	Paths    []string // Paths that appear more than once
+	Speakers []string // Speaker labels that appear more than once
+}
+
+func (e *DuplicateError) Error() string {
+	var parts []string
+	if len(e.Paths) > 0 {
+		parts = append(parts, fmt.Sprintf("duplicate audio files: %s", strings.Join(e.Paths, ", ")))
+	}
+	if len(e.Speakers) > 0 {
+		parts = append(parts, fmt.Sprintf("duplicate speaker labels: %s", strings.Join(e.Speakers, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidateAudioFiles checks if all audio files exist and are accessible.
+// If every file is otherwise valid but two entries share a path (with the
+// same channel mix) or speaker label, a *DuplicateError is returned so
+// callers can choose to treat it as a warning.
 func ValidateAudioFiles(audioFiles []AudioFile) error {
+	stdinCount := 0
 	for i, af := range audioFiles {
 		if af.Path == "" {
 			return fmt.Errorf("audio file %d: path is empty", i+1)
@@ -173,10 +662,69 @@ func ValidateAudioFiles(audioFiles []AudioFile) error {
 			return fmt.Errorf("audio file %d (%s): speaker label is empty", i+1, af.Path)
 		}
 
+		// Standard input can only be read once
+		if af.Path == StdinPath {
+			stdinCount++
+			if stdinCount > 1 {
+				return fmt.Errorf("audio file %d: standard input (%s) can only be used for one audio file", i+1, StdinPath)
+			}
+		}
+
 		// Note: We don't check file existence here as it will be checked during processing
 		// This allows for more flexible error handling
 	}
-	return nil
+
+	return findDuplicates(audioFiles)
+}
+
+// findDuplicates returns a *DuplicateError if any path or speaker label is
+// repeated. Entries for the same path with different channel mixes transcribe
+// different audio, so they are not duplicates.
+func findDuplicates(audioFiles []AudioFile) error {
+	dupErr := &DuplicateError{}
+	seenPaths := make(map[string]int)
+	seenSpeakers := make(map[string]int)
+
+	for _, af := range audioFiles {
+		// Compare absolute paths so "a.wav" and "./a.wav" are caught
+		path := filepath.Clean(af.Path)
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+
+		key := path + "\x00" + string(af.ChannelMix)
+		seenPaths[key]++
+		if seenPaths[key] == 2 {
+			dupErr.Paths = append(dupErr.Paths, af.Path)
+		}
+
+		seenSpeakers[af.Speaker]++
+		if seenSpeakers[af.Speaker] == 2 {
+			dupErr.Speakers = append(dupErr.Speakers, af.Speaker)
+		}
+	}
+
+	if len(dupErr.Paths) == 0 && len(dupErr.Speakers) == 0 {
+		return nil
+	}
+	return dupErr
+}
+
+// ShardAudioFiles returns the audio files assigned to shard index (1-based) of
+// count, distributing files round-robin so shards are disjoint and cover every
+// file. Every machine must be given the files in the same order.
+func ShardAudioFiles(audioFiles []AudioFile, index, count int) ([]AudioFile, error) {
+	if count < 1 || index < 1 || index > count {
+		return nil, fmt.Errorf("invalid shard %d/%d", index, count)
+	}
+
+	var shard []AudioFile
+	for i, af := range audioFiles {
+		if i%count == index-1 {
+			shard = append(shard, af)
+		}
+	}
+	return shard, nil
 }
 
 // GenerateDefaultSpeakerLabels generates default speaker labels if not provided
@@ -0,0 +1,280 @@
+package transcriber
+
+import (
+	"math"
+)
+
+const (
+	// sincZeroCrossings is the number of zero crossings of the sinc kernel
+	// on each side of its center
+	sincZeroCrossings = 8
+
+	// sincTableResolution is the number of kernel table entries per zero crossing
+	sincTableResolution = 256
+
+	// polyphaseZeroCrossings is the number of zero crossings on each side of
+	// the polyphase filter's center. Its weights are computed once per phase
+	// rather than per output sample, so it affords a longer filter with a
+	// sharper cutoff than sincZeroCrossings.
+	polyphaseZeroCrossings = 16
+
+	// polyphaseMaxPhases bounds the number of filter phases, and so the
+	// precomputed weights, a polyphase filter may use. Rate pairs whose
+	// reduced ratio needs more fall back to the table-driven resampler.
+	polyphaseMaxPhases = 1024
+)
+
+// sincTable holds one side of the Blackman-windowed sinc kernel, sampled
+// sincTableResolution times per zero crossing
+var sincTable = buildSincTable()
+
+// buildSincTable precomputes the windowed sinc kernel so that resampling
+// does not evaluate sin and cos for every filter tap
+func buildSincTable() []float64 {
+	n := sincZeroCrossings * sincTableResolution
+	// One extra trailing zero lets lookups interpolate past the last entry
+	table := make([]float64, n+2)
+	for i := 0; i <= n; i++ {
+		x := float64(i) / sincTableResolution
+		table[i] = sinc(x) * blackman(x/sincZeroCrossings)
+	}
+	return table
+}
+
+// sinc returns the normalized sinc function sin(πx)/(πx)
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the Blackman window for u in [-1, 1], centered at 0
+func blackman(u float64) float64 {
+	return 0.42 + 0.5*math.Cos(math.Pi*u) + 0.08*math.Cos(2*math.Pi*u)
+}
+
+// rateConverter converts mono samples between sample rates one output sample
+// at a time, so the same filter can run over whole files or streamed chunks
+type rateConverter interface {
+	// firstTap returns the first source sample index used by output sample i
+	firstTap(i int) int
+	// lastTap returns the last source sample index used by output sample i
+	lastTap(i int) int
+	// sample computes output sample i. window holds source samples starting
+	// at source index base; taps outside it are skipped and the remaining
+	// weights renormalized, which keeps the level steady at the start and
+	// end of the audio.
+	sample(window []int, base, i int) int
+	// outputLen returns the number of output samples for n source samples
+	outputLen(n int) int
+}
+
+// newRateConverter returns the converter for fromRate to toRate: an exact
+// polyphase filter when the rates reduce to a ratio with few enough phases,
+// as the common 44.1kHz and 48kHz rates do, otherwise the table-driven
+// resampler
+func newRateConverter(fromRate, toRate int) rateConverter {
+	if p, ok := newPolyphaseFilter(fromRate, toRate); ok {
+		return p
+	}
+	return newResampler(fromRate, toRate)
+}
+
+// cutoffScale returns the filter cutoff relative to the source Nyquist
+// frequency. When downsampling, the cutoff is lowered to the target rate's
+// Nyquist frequency so that content above it is removed rather than aliased
+// into the speech band.
+func cutoffScale(fromRate, toRate int) float64 {
+	if toRate < fromRate {
+		return float64(toRate) / float64(fromRate)
+	}
+	return 1
+}
+
+// resampler converts between arbitrary sample rates with a windowed-sinc
+// low-pass filter whose weights are interpolated from sincTable at each
+// output sample's fractional source position
+type resampler struct {
+	ratio     float64 // Source samples per output sample
+	scale     float64 // Cutoff relative to the source Nyquist frequency
+	halfWidth float64 // Filter half-width in source samples
+}
+
+// newResampler creates a resampler from fromRate to toRate
+func newResampler(fromRate, toRate int) resampler {
+	scale := cutoffScale(fromRate, toRate)
+	return resampler{
+		ratio:     float64(fromRate) / float64(toRate),
+		scale:     scale,
+		halfWidth: sincZeroCrossings / scale,
+	}
+}
+
+// srcPos returns the source position of output sample i
+func (r resampler) srcPos(i int) float64 {
+	return float64(i) * r.ratio
+}
+
+func (r resampler) firstTap(i int) int {
+	return int(math.Ceil(r.srcPos(i) - r.halfWidth))
+}
+
+func (r resampler) lastTap(i int) int {
+	return int(math.Floor(r.srcPos(i) + r.halfWidth))
+}
+
+func (r resampler) outputLen(n int) int {
+	return int(float64(n) / r.ratio)
+}
+
+// kernel returns the filter weight for a tap t source samples from the center
+func (r resampler) kernel(t float64) float64 {
+	x := math.Abs(t) * r.scale * sincTableResolution
+	i := int(x)
+	if i >= sincZeroCrossings*sincTableResolution {
+		return 0
+	}
+	frac := x - float64(i)
+	return sincTable[i] + (sincTable[i+1]-sincTable[i])*frac
+}
+
+func (r resampler) sample(window []int, base, i int) int {
+	lo := max(r.firstTap(i)-base, 0)
+	hi := min(r.lastTap(i)-base, len(window)-1)
+
+	center := r.srcPos(i) - float64(base)
+	var sum, weights float64
+	for n := lo; n <= hi; n++ {
+		w := r.kernel(center - float64(n))
+		sum += w * float64(window[n])
+		weights += w
+	}
+	if weights == 0 {
+		return 0
+	}
+	return int(math.Round(sum / weights))
+}
+
+// polyphaseFilter resamples by the rational ratio up/down. Output sample i
+// lies at source position i*down/up, whose fractional part is one of only up
+// phases, so the windowed-sinc weights for every phase are computed exactly
+// once up front. Positions are tracked with integer arithmetic, so they never
+// drift over long recordings.
+type polyphaseFilter struct {
+	up, down int         // Reduced ratio: up output samples per down source samples
+	first    []int       // Offset of each phase's first tap from the integer source position
+	weights  [][]float64 // Normalized tap weights for each phase
+}
+
+// newPolyphaseFilter creates a polyphase filter from fromRate to toRate. The
+// boolean is false if the reduced ratio needs more than polyphaseMaxPhases
+// phases.
+func newPolyphaseFilter(fromRate, toRate int) (polyphaseFilter, bool) {
+	g := gcd(fromRate, toRate)
+	up, down := toRate/g, fromRate/g
+	if up > polyphaseMaxPhases {
+		return polyphaseFilter{}, false
+	}
+
+	scale := cutoffScale(fromRate, toRate)
+	halfWidth := polyphaseZeroCrossings / scale
+	p := polyphaseFilter{
+		up:      up,
+		down:    down,
+		first:   make([]int, up),
+		weights: make([][]float64, up),
+	}
+	for phase := range up {
+		frac := float64(phase) / float64(up)
+		lo := int(math.Ceil(frac - halfWidth))
+		hi := int(math.Floor(frac + halfWidth))
+
+		weights := make([]float64, hi-lo+1)
+		var total float64
+		for n := lo; n <= hi; n++ {
+			t := (frac - float64(n)) * scale
+			w := sinc(t) * blackman(t/polyphaseZeroCrossings)
+			weights[n-lo] = w
+			total += w
+		}
+		for k := range weights {
+			weights[k] /= total
+		}
+		p.first[phase] = lo
+		p.weights[phase] = weights
+	}
+	return p, true
+}
+
+// gcd returns the greatest common divisor of a and b
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// position returns the integer source position and phase of output sample i
+func (p polyphaseFilter) position(i int) (int, int) {
+	return i * p.down / p.up, i * p.down % p.up
+}
+
+func (p polyphaseFilter) firstTap(i int) int {
+	pos, phase := p.position(i)
+	return pos + p.first[phase]
+}
+
+func (p polyphaseFilter) lastTap(i int) int {
+	pos, phase := p.position(i)
+	return pos + p.first[phase] + len(p.weights[phase]) - 1
+}
+
+func (p polyphaseFilter) outputLen(n int) int {
+	return n * p.up / p.down
+}
+
+func (p polyphaseFilter) sample(window []int, base, i int) int {
+	pos, phase := p.position(i)
+	weights := p.weights[phase]
+	start := pos + p.first[phase] - base
+
+	// Away from the edges every tap is present and the weights already sum
+	// to one
+	if start >= 0 && start+len(weights) <= len(window) {
+		var sum float64
+		for k, w := range weights {
+			sum += w * float64(window[start+k])
+		}
+		return int(math.Round(sum))
+	}
+
+	var sum, total float64
+	for k, w := range weights {
+		if n := start + k; n >= 0 && n < len(window) {
+			sum += w * float64(window[n])
+			total += w
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(sum / total))
+}
+
+// resample converts samples from fromRate to toRate using a windowed-sinc
+// low-pass filter, avoiding the aliasing that plain interpolation
+// introduces when downsampling
+func resample(samples []int, fromRate, toRate int) []int {
+	if fromRate == toRate || len(samples) == 0 {
+		return samples
+	}
+
+	c := newRateConverter(fromRate, toRate)
+	resampled := make([]int, c.outputLen(len(samples)))
+	for i := range resampled {
+		resampled[i] = c.sample(samples, 0, i)
+	}
+
+	return resampled
+}
@@ -0,0 +1,192 @@
+package transcriber
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// sweep returns a linear sine sweep from 0Hz to the Nyquist frequency of
+// rate over seconds, at amplitude amp
+func sweep(rate int, seconds, amp float64) []int {
+	n := int(float64(rate) * seconds)
+	nyquist := float64(rate) / 2
+	samples := make([]int, n)
+	for i := range samples {
+		t := float64(i) / float64(rate)
+		// Phase of a chirp whose frequency rises linearly from 0 to nyquist
+		phase := math.Pi * nyquist * t * t / seconds
+		samples[i] = int(math.Round(amp * math.Sin(phase)))
+	}
+	return samples
+}
+
+// linearResample is plain linear interpolation, which resample used before
+// it had a low-pass filter, kept as a reference for the aliasing it removes
+func linearResample(samples []int, fromRate, toRate int) []int {
+	ratio := float64(fromRate) / float64(toRate)
+	out := make([]int, int(float64(len(samples))/ratio))
+	for i := range out {
+		pos := float64(i) * ratio
+		j := int(pos)
+		frac := pos - float64(j)
+		next := samples[min(j+1, len(samples)-1)]
+		out[i] = int(math.Round(float64(samples[j])*(1-frac) + float64(next)*frac))
+	}
+	return out
+}
+
+// rmsBetween returns the RMS level of samples between the two times in
+// seconds at rate
+func rmsBetween(samples []int, rate int, from, to float64) float64 {
+	lo, hi := int(from*float64(rate)), min(int(to*float64(rate)), len(samples))
+	var sum float64
+	for _, s := range samples[lo:hi] {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(hi-lo))
+}
+
+// sweepLevels resamples a sweep across the source band and returns the
+// output level in dB relative to the sweep's amplitude while the sweep is in
+// the target band, and while it is above the target Nyquist frequency, where
+// anything left is aliasing
+func sweepLevels(fromRate, toRate int, convert func([]int, int, int) []int) (passband, aliasing float64) {
+	const seconds, amp = 4.0, 16000.0
+	out := convert(sweep(fromRate, seconds, amp), fromRate, toRate)
+
+	// The sweep reaches frequency f at time f/nyquist*seconds
+	at := func(freq float64) float64 { return freq / (float64(fromRate) / 2) * seconds }
+	db := func(rms float64) float64 { return 20 * math.Log10(rms/(amp/math.Sqrt2)) }
+	targetNyquist := float64(toRate) / 2
+	passband = db(rmsBetween(out, toRate, at(500), at(0.8*targetNyquist)))
+	aliasing = db(rmsBetween(out, toRate, at(1.15*targetNyquist), at(0.95*float64(fromRate)/2)))
+	return passband, aliasing
+}
+
+func TestResampleReducesAliasing(t *testing.T) {
+	for _, fromRate := range []int{44100, 48000} {
+		t.Run(fmt.Sprint(fromRate), func(t *testing.T) {
+			passband, aliasing := sweepLevels(fromRate, 16000, resample)
+			_, linear := sweepLevels(fromRate, 16000, linearResample)
+			t.Logf("passband %.2fdB, aliasing %.1fdB (linear interpolation %.1fdB)", passband, aliasing, linear)
+
+			if math.Abs(passband) > 0.5 {
+				t.Errorf("passband level %.2fdB, want within 0.5dB of the input", passband)
+			}
+			if aliasing > -60 {
+				t.Errorf("aliasing %.1fdB, want below -60dB", aliasing)
+			}
+			if aliasing > linear-40 {
+				t.Errorf("aliasing %.1fdB is not at least 40dB below linear interpolation's %.1fdB", aliasing, linear)
+			}
+		})
+	}
+}
+
+// converters are the resampling paths newRateConverter chooses between,
+// built directly so both can be compared on the same rates
+var converters = []struct {
+	name string
+	new  func(fromRate, toRate int) rateConverter
+}{
+	{"polyphase", func(fromRate, toRate int) rateConverter {
+		p, ok := newPolyphaseFilter(fromRate, toRate)
+		if !ok {
+			panic(fmt.Sprintf("no polyphase filter for %d to %d", fromRate, toRate))
+		}
+		return p
+	}},
+	{"table", func(fromRate, toRate int) rateConverter { return newResampler(fromRate, toRate) }},
+}
+
+// convertAll resamples samples with c, as resample does
+func convertAll(c rateConverter, samples []int) []int {
+	out := make([]int, c.outputLen(len(samples)))
+	for i := range out {
+		out[i] = c.sample(samples, 0, i)
+	}
+	return out
+}
+
+// tone returns seconds of a sine wave at freq and amplitude amp sampled at rate
+func tone(rate int, freq, seconds, amp float64) []int {
+	samples := make([]int, int(float64(rate)*seconds))
+	for i := range samples {
+		samples[i] = int(math.Round(amp * math.Sin(2*math.Pi*freq*float64(i)/float64(rate))))
+	}
+	return samples
+}
+
+// errorVsIdealDB resamples in-band tones and returns the RMS difference from
+// the same tones generated directly at toRate, in dB relative to the tones'
+// level. The first and last tenth of a second are skipped, since the filter
+// has only part of its window there.
+func errorVsIdealDB(c rateConverter, fromRate, toRate int) float64 {
+	const seconds, amp = 2.0, 16000.0
+	var errSum, sigSum float64
+	for _, freq := range []float64{440, 1000, 3000, 6000} {
+		got := convertAll(c, tone(fromRate, freq, seconds, amp))
+		want := tone(toRate, freq, seconds, amp)
+		edge := toRate / 10
+		for i := edge; i < min(len(got), len(want))-edge; i++ {
+			d := float64(got[i] - want[i])
+			errSum += d * d
+			sigSum += float64(want[i]) * float64(want[i])
+		}
+	}
+	return 10 * math.Log10(errSum/sigSum)
+}
+
+func TestResampleErrorVsIdeal(t *testing.T) {
+	for _, fromRate := range []int{44100, 48000} {
+		errs := make(map[string]float64)
+		for _, conv := range converters {
+			errs[conv.name] = errorVsIdealDB(conv.new(fromRate, 16000), fromRate, 16000)
+		}
+		t.Logf("%d to 16000: polyphase %.1fdB, table %.1fdB", fromRate, errs["polyphase"], errs["table"])
+
+		if errs["polyphase"] > -60 {
+			t.Errorf("%d: polyphase error %.1fdB, want below -60dB", fromRate, errs["polyphase"])
+		}
+		if errs["polyphase"] > errs["table"] {
+			t.Errorf("%d: polyphase error %.1fdB is worse than the table's %.1fdB", fromRate, errs["polyphase"], errs["table"])
+		}
+	}
+}
+
+func TestNewRateConverterFallback(t *testing.T) {
+	tests := []struct {
+		fromRate      int
+		wantPolyphase bool
+	}{
+		{44100, true},
+		{48000, true},
+		{22050, true},
+		{44101, false}, // Reduces to 16000/44101, too many phases
+	}
+	for _, tt := range tests {
+		_, polyphase := newRateConverter(tt.fromRate, 16000).(polyphaseFilter)
+		if polyphase != tt.wantPolyphase {
+			t.Errorf("newRateConverter(%d, 16000) polyphase = %v, want %v", tt.fromRate, polyphase, tt.wantPolyphase)
+		}
+	}
+}
+
+// BenchmarkResample times each resampling path over 10 seconds of audio and
+// reports its error against ideal output (see errorVsIdealDB) as dB-error,
+// so the accuracy/speed tradeoff is visible in one run
+func BenchmarkResample(b *testing.B) {
+	for _, fromRate := range []int{44100, 48000} {
+		samples := sweep(fromRate, 10, 16000)
+		for _, conv := range converters {
+			c := conv.new(fromRate, 16000)
+			b.Run(fmt.Sprintf("%d/%s", fromRate, conv.name), func(b *testing.B) {
+				for b.Loop() {
+					convertAll(c, samples)
+				}
+				b.ReportMetric(errorVsIdealDB(c, fromRate, 16000), "dB-error")
+			})
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package transcriber
+
+import (
+	"skriptble.dev/podcast-tools/models"
+)
+
+const (
+	// DefaultSilenceThreshold is the RMS level below which audio counts as
+	// silence when trimming, about -40 dBFS
+	DefaultSilenceThreshold = 0.01
+
+	// silencePadding is the seconds of audio kept on each side of detected
+	// speech so soft word onsets and endings are not clipped
+	silencePadding = 0.5
+)
+
+// speechBounds returns the sample range of samples between leading and
+// trailing silence: from the first to the last energyWindow whose RMS level
+// is at least threshold, widened by silencePadding on each side. If no window
+// reaches threshold, the full range is returned.
+func speechBounds(samples []float32, threshold float32) (start, end int) {
+	first, last := -1, -1
+	for i, level := range energyEnvelope(samples) {
+		if level >= threshold {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return 0, len(samples)
+	}
+
+	padding := int(silencePadding * SampleRate)
+	start = max(first*energyWindow-padding, 0)
+	end = min((last+1)*energyWindow+padding, len(samples))
+	return start, end
+}
+
+// trimSilence returns samples without their leading and trailing silence
+// when config.TrimSilence is set, along with the seconds trimmed from the
+// start, which must be added back to timestamps to match the original audio
+func trimSilence(samples []float32, config WhisperConfig) ([]float32, float64) {
+	if !config.TrimSilence {
+		return samples, 0
+	}
+
+	threshold := config.SilenceThreshold
+	if threshold <= 0 {
+		threshold = DefaultSilenceThreshold
+	}
+	start, end := speechBounds(samples, threshold)
+
+	if start > 0 || end < len(samples) {
+		config.logger().Debug("Trimmed silence",
+			"leading_seconds", roundForLog(float64(start)/SampleRate),
+			"trailing_seconds", roundForLog(float64(len(samples)-end)/SampleRate))
+	}
+	return samples[start:end], float64(start) / SampleRate
+}
+
+// shiftSegments adds offset seconds to the segment and word timestamps, for
+// segments transcribed from audio starting offset seconds into a file
+func shiftSegments(segments []models.Segment, offset float64) {
+	if offset == 0 {
+		return
+	}
+	for i := range segments {
+		segments[i].StartTime += offset
+		segments[i].EndTime += offset
+		for j := range segments[i].Words {
+			segments[i].Words[j].StartTime += offset
+			segments[i].Words[j].EndTime += offset
+		}
+	}
+}
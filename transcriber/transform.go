@@ -0,0 +1,147 @@
+package transcriber
+
+import (
+	"fmt"
+
+	"skriptble.dev/podcast-tools/models"
+)
+
+// Transform is a post-processing step applied to a finished transcript
+type Transform interface {
+	Apply(transcript *models.Transcript) error
+}
+
+// TransformFunc adapts an ordinary function to the Transform interface
+type TransformFunc func(transcript *models.Transcript) error
+
+// Apply calls f(transcript)
+func (f TransformFunc) Apply(transcript *models.Transcript) error {
+	return f(transcript)
+}
+
+// ApplyTransforms runs transforms in order, stopping at the first error
+func ApplyTransforms(transcript *models.Transcript, transforms []Transform) error {
+	for i, transform := range transforms {
+		if err := transform.Apply(transcript); err != nil {
+			return fmt.Errorf("transform %d (%T) failed: %w", i+1, transform, err)
+		}
+	}
+	return nil
+}
+
+// TrimSilenceEdges shifts all timestamps so the transcript starts at the
+// first spoken segment, and optionally ends it at the last spoken word
+type TrimSilenceEdges struct {
+	Trailing bool // Also trim silence after the last word; needs word timings
+}
+
+// Apply implements Transform
+func (t TrimSilenceEdges) Apply(transcript *models.Transcript) error {
+	transcript.TrimSilenceEdges()
+	if t.Trailing {
+		transcript.TrimTrailingSilence()
+	}
+	return nil
+}
+
+// RenameSpeakers relabels speakers, for fixing generic track labels without
+// transcribing again
+type RenameSpeakers struct {
+	Names map[string]string // New label for each old label
+}
+
+// Apply implements Transform
+func (r RenameSpeakers) Apply(transcript *models.Transcript) error {
+	transcript.RenameSpeakers(r.Names)
+	return nil
+}
+
+// RemoveRepetitions collapses runs of identical consecutive segments from
+// one speaker, which whisper produces when it loops on silence or music
+type RemoveRepetitions struct {
+	MaxRepeats int // Segments of each run to keep; less than 1 keeps one
+}
+
+// Apply implements Transform
+func (r RemoveRepetitions) Apply(transcript *models.Transcript) error {
+	transcript.RemoveRepetitions(r.MaxRepeats)
+	return nil
+}
+
+// RemoveFillers deletes filler words such as "um" and "uh" from segment
+// text and word timings, matching single words
+type RemoveFillers struct {
+	Words []string // Filler words to remove; nil uses models.DefaultFillers
+}
+
+// Apply implements Transform
+func (r RemoveFillers) Apply(transcript *models.Transcript) error {
+	transcript.RemoveFillers(r.Words)
+	return nil
+}
+
+// MergeAdjacentSpeakers combines consecutive segments from the same speaker
+// separated by at most MaxGap seconds into a single segment
+type MergeAdjacentSpeakers struct {
+	MaxGap float64 // Largest gap in seconds between segments that are merged
+}
+
+// Apply implements Transform
+func (m MergeAdjacentSpeakers) Apply(transcript *models.Transcript) error {
+	transcript.MergeAdjacentSpeakers(m.MaxGap)
+	return nil
+}
+
+// SplitLongSegments divides segments longer than MaxSeconds at word
+// boundaries, for subtitle-friendly segment lengths
+type SplitLongSegments struct {
+	MaxSeconds float64 // Longest segment in seconds before it is split
+}
+
+// Apply implements Transform
+func (s SplitLongSegments) Apply(transcript *models.Transcript) error {
+	transcript.SplitLongSegments(s.MaxSeconds)
+	return nil
+}
+
+// SortBySpeaker groups segments by speaker instead of interleaving them in
+// time order, for reading one speaker's lines at a time
+type SortBySpeaker struct{}
+
+// Apply implements Transform
+func (SortBySpeaker) Apply(transcript *models.Transcript) error {
+	transcript.SortBySpeaker()
+	return nil
+}
+
+// Shift moves all timestamps by Seconds, clamping at zero, to align the
+// transcript with an edited master that added or removed leading content
+type Shift struct {
+	Seconds float64 // Offset in seconds; negative values move timestamps earlier
+}
+
+// Apply implements Transform
+func (s Shift) Apply(transcript *models.Transcript) error {
+	transcript.Shift(s.Seconds)
+	return nil
+}
+
+// Window keeps only the segments overlapping the time range from Start to
+// End in seconds, for cutting a clip's transcript out of an episode
+type Window struct {
+	Start, End float64
+	Clip       bool // Clip segments at the edges to the window bounds
+}
+
+// Apply implements Transform
+func (w Window) Apply(transcript *models.Transcript) error {
+	if w.End <= w.Start {
+		return fmt.Errorf("window end %.3fs is not after start %.3fs", w.End, w.Start)
+	}
+	if w.Clip {
+		transcript.Segments = transcript.WindowClipped(w.Start, w.End).Segments
+	} else {
+		transcript.Segments = transcript.Window(w.Start, w.End).Segments
+	}
+	return nil
+}
@@ -1,36 +1,99 @@
 package transcriber
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/go-audio/audio"
 	"github.com/go-audio/wav"
 	"skriptble.dev/podcast-tools/models"
 
 	whisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
 )
 
+// retryBackoff is the delay before the first retry of a failed
+// transcription; it doubles with each further retry
+const retryBackoff = 500 * time.Millisecond
+
+// SampleRate is the sample rate in Hz of audio returned by LoadAudio
+const SampleRate = whisper.SampleRate
+
+// StdinPath is the audio file path that reads raw 16kHz mono float32
+// little-endian samples from standard input instead of a file
+const StdinPath = "-"
+
+const (
+	// streamingThreshold is the file size above which WAV files are decoded
+	// in chunks instead of loading the full PCM buffer into memory
+	streamingThreshold = 512 << 20 // 512 MB
+
+	// streamChunkFrames is the number of frames decoded per chunk when streaming
+	streamChunkFrames = 1 << 16
+)
+
 // WhisperConfig holds configuration for Whisper transcription
 type WhisperConfig struct {
 	ModelPath string // Path to the Whisper model file
 	Language  string // Language code (e.g., "en", "es"), "auto" for detection
-	Verbose   bool   // Enable verbose logging
+	// BeamSize must be 0. The whisper Go binding always decodes greedily, and
+	// whisper.cpp ignores the beam width under greedy decoding, so a beam
+	// search width is rejected rather than silently having no effect.
+	BeamSize    int
+	Temperature float32 // Sampling temperature (0 = whisper's default)
+	Translate   bool    // Translate speech to English instead of transcribing in the source language
+	// InitialPrompt is text the decoder is primed with before each file, used
+	// to bias spelling of proper nouns and domain jargon (e.g., "Kubernetes, Grafana")
+	InitialPrompt string
+	// Normalize scales quiet audio up so its peak is near full scale before
+	// transcription, so whisper does not miss words in low-level sections
+	Normalize bool
+	// TrimSilence skips leading and trailing audio quieter than
+	// SilenceThreshold, saving time on tracks with long silences and avoiding
+	// text hallucinated from silence. Timestamps still match the original audio.
+	TrimSilence      bool
+	SilenceThreshold float32 // RMS level counted as silence (0 = DefaultSilenceThreshold)
+	// ChannelMix selects how multichannel files are reduced to mono, such as
+	// MixLeft for a track whose speaker is panned hard left (empty = MixAverage)
+	ChannelMix ChannelMix
+	// Deprecated: Verbose has no effect; set Logger to receive progress
+	Verbose bool
+	// CacheDir, if set, is a directory of cached transcriptions. Audio that
+	// was transcribed before with the same model file and decoding settings
+	// reuses the cached segments instead of running whisper again.
+	CacheDir string
+	// Logger receives progress at info level, per-file audio details at
+	// debug level, and warnings. When nil, nothing is logged.
+	Logger *slog.Logger
 }
 
 // WhisperTranscriber wraps the whisper.cpp functionality
 type WhisperTranscriber struct {
 	model  whisper.Model
 	config WhisperConfig
+	logger *slog.Logger
 }
 
+// errBeamSearch is returned for a non-zero WhisperConfig.BeamSize
+var errBeamSearch = errors.New("beam search is not supported: the whisper Go binding only decodes greedily")
+
 // NewWhisperTranscriber creates a new Whisper transcriber instance
 func NewWhisperTranscriber(config WhisperConfig) (*WhisperTranscriber, error) {
 	// Validate model path
 	if config.ModelPath == "" {
 		return nil, fmt.Errorf("model path is required")
 	}
+	if config.BeamSize != 0 {
+		return nil, errBeamSearch
+	}
 
 	// Check if model file exists
 	if _, err := os.Stat(config.ModelPath); os.IsNotExist(err) {
@@ -38,9 +101,8 @@ func NewWhisperTranscriber(config WhisperConfig) (*WhisperTranscriber, error) {
 	}
 
 	// Load the Whisper model
-	if config.Verbose {
-		fmt.Printf("Loading Whisper model from %s...\n", config.ModelPath)
-	}
+	logger := config.logger()
+	logger.Info("Loading Whisper model", "path", config.ModelPath)
 
 	model, err := whisper.New(config.ModelPath)
 	if err != nil {
@@ -52,19 +114,30 @@ func NewWhisperTranscriber(config WhisperConfig) (*WhisperTranscriber, error) {
 		config.Language = "auto"
 	}
 
-	if config.Verbose {
-		fmt.Printf("Model loaded successfully\n")
-		if model.IsMultilingual() {
-			fmt.Printf("Multilingual model detected, supported languages: %d\n", len(model.Languages()))
-		}
-	}
+	logger.Info("Model loaded successfully")
+	logModelLanguages(logger, model, config)
 
 	return &WhisperTranscriber{
 		model:  model,
 		config: config,
+		logger: logger,
 	}, nil
 }
 
+// logModelLanguages logs the model's language support, noting when an
+// English-only model overrides the configured language or translation
+func logModelLanguages(logger *slog.Logger, model whisper.Model, config WhisperConfig) {
+	if model.IsMultilingual() {
+		logger.Info("Multilingual model detected", "languages", len(model.Languages()))
+	} else if config.Language == "auto" {
+		// Language detection is skipped and whisper transcribes as English
+		logger.Info("Model is English-only, language forced to English (auto-detection unavailable)")
+	}
+	if config.Translate && !model.IsMultilingual() {
+		logger.Info("Model is English-only, translation is unavailable")
+	}
+}
+
 // Close releases resources associated with the transcriber
 func (wt *WhisperTranscriber) Close() error {
 	if wt.model != nil {
@@ -73,197 +146,642 @@ func (wt *WhisperTranscriber) Close() error {
 	return nil
 }
 
-// TranscribeFile transcribes an audio file and returns segments with speaker label
+// ModelInfo describes the model a transcriber has loaded
+type ModelInfo struct {
+	Name         string   // Model name, as accepted by --model (e.g., "base.en")
+	Path         string   // Full path to the model file
+	Size         int64    // File size in bytes
+	Multilingual bool     // False for English-only (.en) models
+	Languages    []string // Codes of the languages the model can transcribe
+}
+
+// ModelInfo returns the name, size, and language support of the loaded
+// model. English-only models report just "en", since whisper lists every
+// language it knows for them too.
+func (wt *WhisperTranscriber) ModelInfo() ModelInfo {
+	info := ModelInfo{
+		Name:         ModelName(wt.config.ModelPath),
+		Path:         wt.config.ModelPath,
+		Multilingual: wt.model.IsMultilingual(),
+		Languages:    []string{"en"},
+	}
+	if stat, err := os.Stat(wt.config.ModelPath); err == nil {
+		info.Size = stat.Size()
+	}
+	if info.Multilingual {
+		info.Languages = wt.model.Languages()
+	}
+	return info
+}
+
+// TranscribeFile transcribes an audio file and returns segments with speaker
+// label, each recording audioPath as its Source
 func (wt *WhisperTranscriber) TranscribeFile(audioPath string, speakerLabel string) ([]models.Segment, error) {
-	if wt.config.Verbose {
-		fmt.Printf("Transcribing %s (speaker: %s)...\n", filepath.Base(audioPath), speakerLabel)
+	segments, _, _, err := wt.transcribeFile(context.Background(), AudioFile{Path: audioPath, Speaker: speakerLabel}, false, 0)
+	return segments, err
+}
+
+// TranscribeStream transcribes raw audio read from r and returns segments with
+// speaker label. The stream must contain whisper.SampleRate (16kHz) mono
+// float32 little-endian samples, e.g. the output of
+// "ffmpeg -i input -f f32le -ac 1 -ar 16000 -".
+func (wt *WhisperTranscriber) TranscribeStream(r io.Reader, speakerLabel string) ([]models.Segment, error) {
+	wt.logger.Info("Transcribing stream", "speaker", speakerLabel)
+
+	audioData, err := readRawPCM(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audio stream: %w", err)
 	}
+	normalizeAudio(audioData, wt.config)
+	audioData, lead := trimSilence(audioData, wt.config)
 
-	startTime := time.Now()
+	segments, _, err := wt.transcribeAudio(context.Background(), audioData, speakerLabel, "")
+	shiftSegments(segments, lead)
+	return segments, err
+}
+
+// transcribeFile transcribes an audio file, also returning the audio's energy
+// envelope when withEnergy is set and the language whisper transcribed in.
+// A path of StdinPath reads raw samples from standard input as described in
+// TranscribeStream. The file's language and channel mix, if set, override
+// the configured ones. Failed transcriptions are retried up to maxRetries
+// times. Cancelling ctx stops transcription at the next segment boundary.
+func (wt *WhisperTranscriber) transcribeFile(ctx context.Context, file AudioFile, withEnergy bool, maxRetries int) ([]models.Segment, []float32, string, error) {
+	name := "stdin"
+	if file.Path != StdinPath {
+		name = filepath.Base(file.Path)
+	}
+	wt.logger.Info("Transcribing", "file", name, "speaker", file.Speaker)
 
-	// Create a new context for this transcription
-	ctx, err := wt.model.NewContext()
+	audioData, err := readInput(file, wt.config, wt.logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create context: %w", err)
+		return nil, nil, "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, "", err
 	}
 
-	// Set language
-	if wt.config.Language != "" && wt.config.Language != "auto" {
-		if err := ctx.SetLanguage(wt.config.Language); err != nil {
-			return nil, fmt.Errorf("failed to set language: %w", err)
-		}
+	// Measure energy before normalizing so tracks keep their relative levels
+	var energy []float32
+	if withEnergy {
+		energy = energyEnvelope(audioData)
 	}
+	normalizeAudio(audioData, wt.config)
+	audioData, lead := trimSilence(audioData, wt.config)
 
-	// Load and process the audio file
-	// Note: whisper.cpp requires audio at whisper.SampleRate (16kHz), mono, float32
-	audioData, err := loadAudioFile(audioPath, wt.config.Verbose)
+	segments, detected, err := wt.transcribeAudioCached(ctx, audioData, file.Speaker, file.Language, maxRetries)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	shiftSegments(segments, lead)
+	setSource(segments, file.Path)
+	return segments, energy, detected, nil
+}
+
+// setSource records path as the source of each segment
+func setSource(segments []models.Segment, path string) {
+	for i := range segments {
+		segments[i].Source = path
+	}
+}
+
+// readInput loads 16kHz mono samples from file, or raw samples from standard
+// input when its path is StdinPath, logging the audio's details to logger
+func readInput(file AudioFile, config WhisperConfig, logger *slog.Logger) ([]float32, error) {
+	mix := config.ChannelMix
+	if file.ChannelMix != "" {
+		mix = file.ChannelMix
+	}
+
+	var audioData []float32
+	var err error
+	if file.Path == StdinPath {
+		audioData, err = readRawPCM(os.Stdin)
+	} else {
+		// Note: whisper.cpp requires audio at whisper.SampleRate (16kHz), mono, float32
+		audioData, err = loadAudioFile(file.Path, mix, logger)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load audio file: %w", err)
 	}
+	return audioData, nil
+}
+
+// transcribeAudioWithRetry calls transcribeAudio, retrying up to maxRetries
+// times after a failure with an exponential backoff starting at retryBackoff.
+// Each attempt uses a fresh whisper context. Cancellation is not retried.
+func (wt *WhisperTranscriber) transcribeAudioWithRetry(ctx context.Context, audioData []float32, speakerLabel string, language string, maxRetries int) ([]models.Segment, string, error) {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		segments, detected, err := wt.transcribeAudio(ctx, audioData, speakerLabel, language)
+		if err == nil || attempt >= maxRetries || ctx.Err() != nil {
+			return segments, detected, err
+		}
+
+		wt.logger.Info("Transcription failed, retrying", "speaker", speakerLabel,
+			"attempt", attempt+1, "attempts", maxRetries+1, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// transcribeAudio runs whisper over 16kHz mono float32 samples and returns
+// segments with speaker label and the language whisper transcribed in
+// (detected when the language is "auto"). A non-empty language overrides the
+// configured language. If ctx is cancelled, ctx.Err() is returned once
+// whisper reaches the next segment boundary.
+func (wt *WhisperTranscriber) transcribeAudio(ctx context.Context, audioData []float32, speakerLabel string, language string) ([]models.Segment, string, error) {
+	startTime := time.Now()
+
+	// Create a new whisper context for this transcription
+	wctx, err := wt.model.NewContext()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create context: %w", err)
+	}
+
+	// Set language, preferring the per-file override
+	if language == "" {
+		language = wt.config.Language
+	}
+	if language != "" && language != "auto" {
+		if err := wctx.SetLanguage(language); err != nil {
+			return nil, "", fmt.Errorf("failed to set language: %w", err)
+		}
+	}
+
+	// Translate to English; the source language is still set or detected as above
+	if wt.config.Translate {
+		wctx.SetTranslate(true)
+	}
+
+	// Prime the decoder with the vocabulary hint. Each file gets its own
+	// context, so every transcriber in the pool applies the same prompt.
+	if wt.config.InitialPrompt != "" {
+		wctx.SetInitialPrompt(wt.config.InitialPrompt)
+	}
+
+	// Set decoding parameters, keeping whisper's defaults for zero values
+	if wt.config.Temperature > 0 {
+		wctx.SetTemperature(wt.config.Temperature)
+	}
+
+	// Token timestamps are needed for word-level timings
+	wctx.SetTokenTimestamps(true)
 
 	// Process the audio
 	// The Process method now requires callback functions (added in newer versions of whisper.cpp)
-	// We pass nil for all callbacks since we just want to iterate segments after processing
-	if err := ctx.Process(audioData, nil, nil, nil); err != nil {
-		return nil, fmt.Errorf("failed to process audio: %w", err)
+	// The encoder-begin callback aborts processing once ctx is cancelled;
+	// segments are iterated after processing, so the others are nil
+	continueEncoding := func() bool { return ctx.Err() == nil }
+	if err := wctx.Process(audioData, continueEncoding, nil, nil); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		return nil, "", fmt.Errorf("failed to process audio: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
 	}
 
+	// Whisper reports the language it transcribed in, whether set or detected
+	detected := wctx.DetectedLanguage()
+
 	// Extract segments
 	var segments []models.Segment
 	for {
-		segment, err := ctx.NextSegment()
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+
+		segment, err := wctx.NextSegment()
 		if err != nil {
 			break // No more segments
 		}
 
 		// Convert whisper segment to our model
 		seg := models.Segment{
-			Speaker:   speakerLabel,
-			Text:      segment.Text,
-			StartTime: segment.Start.Seconds(),
-			EndTime:   segment.End.Seconds(),
+			Speaker:    speakerLabel,
+			Text:       segment.Text,
+			StartTime:  segment.Start.Seconds(),
+			EndTime:    segment.End.Seconds(),
+			SourceIDs:  []int{segment.Num},
+			Words:      wordsFromTokens(wctx, segment.Tokens),
+			Confidence: segmentConfidence(wctx, segment.Tokens),
 		}
 
 		segments = append(segments, seg)
 	}
 
-	if wt.config.Verbose {
-		duration := time.Since(startTime)
-		fmt.Printf("Transcription completed for %s in %v (%d segments)\n",
-			speakerLabel, duration, len(segments))
+	wt.logger.Info("Transcription completed", "speaker", speakerLabel,
+		"duration", time.Since(startTime), "segments", len(segments), "language", detected)
+
+	return segments, detected, nil
+}
+
+// segmentConfidence returns the mean probability of a segment's text tokens
+func segmentConfidence(ctx whisper.Context, tokens []whisper.Token) float64 {
+	var sum float64
+	count := 0
+	for _, token := range tokens {
+		if !ctx.IsText(token) {
+			continue
+		}
+		sum += float64(token.P)
+		count++
+	}
+	if count == 0 {
+		return 0
 	}
+	return sum / float64(count)
+}
+
+// wordsFromTokens groups a segment's text tokens into words. Whisper tokens
+// are sub-word pieces; a token starting with a space begins a new word and any
+// other token (including punctuation) continues the previous one. A word's
+// confidence is the mean probability of its tokens.
+func wordsFromTokens(ctx whisper.Context, tokens []whisper.Token) []models.Word {
+	var words []models.Word
+	tokenCount := 0
+	for _, token := range tokens {
+		if !ctx.IsText(token) || strings.TrimSpace(token.Text) == "" {
+			continue
+		}
 
-	return segments, nil
+		if len(words) == 0 || strings.HasPrefix(token.Text, " ") {
+			if len(words) > 0 {
+				words[len(words)-1].Confidence /= float64(tokenCount)
+			}
+			words = append(words, models.Word{
+				Text:       strings.TrimSpace(token.Text),
+				StartTime:  token.Start.Seconds(),
+				EndTime:    token.End.Seconds(),
+				Confidence: float64(token.P),
+			})
+			tokenCount = 1
+			continue
+		}
+
+		word := &words[len(words)-1]
+		word.Text += strings.TrimSpace(token.Text)
+		word.EndTime = token.End.Seconds()
+		word.Confidence += float64(token.P)
+		tokenCount++
+	}
+	if len(words) > 0 {
+		words[len(words)-1].Confidence /= float64(tokenCount)
+	}
+
+	return words
 }
 
-// loadAudioFile loads a WAV file and converts it to the format required by Whisper
-// Whisper requires: whisper.SampleRate (16kHz), mono channel, float32 PCM
-func loadAudioFile(audioPath string, verbose bool) ([]float32, error) {
-	// Open the WAV file
+// LoadAudio decodes an audio file into the 16kHz mono float32 samples whisper
+// expects, without loading a model. It performs the same decoding as
+// TranscribeFile, so it can be used to check that files are readable before
+// starting a long transcription. Multichannel audio is averaged to mono.
+// The audio's details are logged to logger at debug level; it may be nil.
+func LoadAudio(audioPath string, logger *slog.Logger) ([]float32, error) {
+	return LoadAudioChannel(audioPath, MixAverage, logger)
+}
+
+// LoadAudioChannel is like LoadAudio but reduces multichannel audio to mono
+// as selected by mix, such as MixLeft for the left side of a stereo recording
+func LoadAudioChannel(audioPath string, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	return loadAudioFile(audioPath, mix, orDiscard(logger))
+}
+
+// loadAudioFile loads a WAV, MP3, FLAC, or Ogg (Vorbis or Opus) file and
+// converts it to the format required by Whisper.
+// Whisper requires: whisper.SampleRate (16kHz), mono channel, float32 PCM.
+// Multichannel audio is reduced to mono as selected by mix, and the audio's
+// details are logged to logger at debug level.
+func loadAudioFile(audioPath string, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	// Open the audio file
 	file, err := os.Open(audioPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open audio file: %w", err)
 	}
 	defer file.Close()
 
-	// Create WAV decoder
-	decoder := wav.NewDecoder(file)
-	if !decoder.IsValidFile() {
-		return nil, fmt.Errorf("invalid WAV file: %s", audioPath)
-	}
+	return decodeContainer(file, audioPath, func(container string) ([]float32, error) {
+		switch container {
+		case containerMP3:
+			return loadMP3(file, mix, logger)
+		case containerFLAC:
+			return loadFLAC(file, mix, logger)
+		case containerOgg:
+			return loadOgg(file, mix, logger)
+		}
 
+		// Create WAV decoder
+		decoder := wav.NewDecoder(file)
+		if !decoder.IsValidFile() {
+			return nil, fmt.Errorf("invalid WAV file: %s", audioPath)
+		}
+
+		// Decode very large files in chunks rather than loading all PCM data at once
+		if info, err := file.Stat(); err == nil && info.Size() > streamingThreshold {
+			logger.Debug("Large file, decoding in chunks", "size_mb", info.Size()>>20)
+			return loadAudioStream(decoder, mix, logger)
+		}
+
+		return loadWAVBuffered(decoder, mix, logger)
+	})
+}
+
+// loadWAVBuffered decodes a whole WAV file into memory at once and converts
+// it in a single pass. Peak memory is several times the source PCM data, so
+// loadAudioFile uses loadAudioStream above streamingThreshold instead.
+func loadWAVBuffered(decoder *wav.Decoder, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
 	// Read the audio buffer
 	buf, err := decoder.FullPCMBuffer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read audio data: %w", err)
 	}
 
-	if verbose {
-		fmt.Printf("  Audio format: %d Hz, %d bit, %d channel(s)\n",
-			buf.Format.SampleRate, decoder.BitDepth, buf.Format.NumChannels)
+	logAudioFormat(logger, "WAV", buf.Format.SampleRate, int(decoder.BitDepth), buf.Format.NumChannels)
+
+	if decoder.BitDepth == 8 {
+		unbiasUnsigned8(buf.Data)
+	}
+	return convertSamples(buf.Data, buf.Format.NumChannels, buf.Format.SampleRate, int(decoder.BitDepth), mix, logger)
+}
+
+// logAudioFormat logs a decoded file's container and sample format. A
+// bitDepth of 0 is omitted for compressed formats without one.
+func logAudioFormat(logger *slog.Logger, container string, sampleRate, bitDepth, channels int) {
+	attrs := []any{"container", container, "sample_rate", sampleRate}
+	if bitDepth > 0 {
+		attrs = append(attrs, "bit_depth", bitDepth)
 	}
+	logger.Debug("Audio format", append(attrs, "channels", channels)...)
+}
+
+// logLoaded logs the number of 16kHz samples decoded
+func logLoaded(logger *slog.Logger, samples int) {
+	seconds := float64(samples) / float64(whisper.SampleRate)
+	logger.Debug("Loaded samples", "samples", samples, "seconds", roundForLog(seconds))
+}
 
+// convertSamples mixes interleaved integer PCM samples to mono, resamples them
+// to whisper.SampleRate and normalizes them to float32 in [-1.0, 1.0]. Every
+// decoder feeds its raw samples through here so the conversion is identical
+// regardless of the input container.
+func convertSamples(samples []int, numChannels, sampleRate, bitDepth int, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
 	// Convert to mono if stereo
-	var monoSamples []int
-	if buf.Format.NumChannels == 1 {
-		monoSamples = buf.Data
-	} else {
-		// Average channels to convert to mono
-		monoSamples = make([]int, len(buf.Data)/buf.Format.NumChannels)
-		for i := 0; i < len(monoSamples); i++ {
-			sum := 0
-			for ch := 0; ch < buf.Format.NumChannels; ch++ {
-				sum += buf.Data[i*buf.Format.NumChannels+ch]
-			}
-			monoSamples[i] = sum / buf.Format.NumChannels
-		}
+	channel, err := mix.channel(numChannels)
+	if err != nil {
+		return nil, err
 	}
+	monoSamples := mixToMono(samples, numChannels, channel)
 
 	// Resample to whisper.SampleRate if needed
 	targetRate := whisper.SampleRate
 	sourceSamples := monoSamples
-	if buf.Format.SampleRate != targetRate {
-		if verbose {
-			fmt.Printf("  Resampling from %d Hz to %d Hz\n", buf.Format.SampleRate, targetRate)
-		}
-		sourceSamples = resample(monoSamples, buf.Format.SampleRate, targetRate)
+	if sampleRate != targetRate {
+		logger.Debug("Resampling", "from_hz", sampleRate, "to_hz", targetRate)
+		sourceSamples = resample(monoSamples, sampleRate, targetRate)
 	}
 
 	// Convert to float32 normalized to [-1.0, 1.0]
 	floatSamples := make([]float32, len(sourceSamples))
+	maxVal := normalizationFactor(bitDepth)
+	for i, sample := range sourceSamples {
+		floatSamples[i] = normalizeSample(sample, maxVal)
+	}
 
-	// Determine the normalization factor based on bit depth
-	// For 16-bit: max value is 2^15 (32768)
-	// For 24-bit: max value is 2^23 (8388608)
-	// For 32-bit (int or float): max value is 2^31 (2147483648)
-	bitDepth := decoder.BitDepth
-	var maxVal float32
+	logLoaded(logger, len(floatSamples))
 
-	switch bitDepth {
-	case 16:
-		maxVal = 32768.0 // 2^15
-	case 24:
-		maxVal = 8388608.0 // 2^23
-	case 32:
-		maxVal = 2147483648.0 // 2^31
-	default:
-		// Fallback for other bit depths
-		maxVal = float32(int64(1) << uint(bitDepth-1))
+	return floatSamples, nil
+}
+
+// loadAudioStream decodes a WAV file in fixed-size chunks, mixing, resampling
+// and normalizing each chunk as it is read. Only the 16kHz float32 output is
+// kept in memory, rather than the full source PCM data. The output matches
+// the single-pass path in loadAudioFile.
+func loadAudioStream(decoder *wav.Decoder, mix ChannelMix, logger *slog.Logger) ([]float32, error) {
+	if err := decoder.FwdToPCM(); err != nil {
+		return nil, fmt.Errorf("failed to read audio data: %w", err)
 	}
 
-	for i, sample := range sourceSamples {
-		floatSamples[i] = float32(sample) / maxVal
+	format := decoder.Format()
+	numChannels := format.NumChannels
+	if numChannels <= 0 || decoder.BitDepth == 0 {
+		return nil, fmt.Errorf("invalid WAV format: %d channel(s), %d bit", numChannels, decoder.BitDepth)
+	}
+	channel, err := mix.channel(numChannels)
+	if err != nil {
+		return nil, err
+	}
+
+	logAudioFormat(logger, "WAV", format.SampleRate, int(decoder.BitDepth), numChannels)
+
+	targetRate := whisper.SampleRate
+	ratio := float64(format.SampleRate) / float64(targetRate)
+	if format.SampleRate != targetRate {
+		logger.Debug("Resampling", "from_hz", format.SampleRate, "to_hz", targetRate)
+	}
+	r := newRateConverter(format.SampleRate, targetRate)
+
+	// Estimate the output size from the PCM chunk size to avoid regrowing
+	bytesPerFrame := int(decoder.BitDepth) / 8 * numChannels
+	estimatedFrames := 0
+	if bytesPerFrame > 0 {
+		estimatedFrames = decoder.PCMSize / bytesPerFrame
+	}
+	floatSamples := make([]float32, 0, int(float64(estimatedFrames)/ratio)+1)
+	maxVal := normalizationFactor(int(decoder.BitDepth))
+
+	buf := &audio.IntBuffer{
+		Format: format,
+		Data:   make([]int, streamChunkFrames*numChannels),
+	}
+
+	// pending holds mono source samples not yet consumed by the resampler;
+	// pending[0] is source frame number base
+	var pending []int
+	base := 0
+
+	for {
+		n, err := decoder.PCMBuffer(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audio data: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		frames := buf.Data[:n-n%numChannels]
+		if decoder.BitDepth == 8 {
+			unbiasUnsigned8(frames)
+		}
+		pending = append(pending, mixToMono(frames, numChannels, channel)...)
+
+		// Emit every output sample whose filter window has been fully read
+		for {
+			i := len(floatSamples)
+			if r.lastTap(i)-base >= len(pending) {
+				break
+			}
+			floatSamples = append(floatSamples, normalizeSample(r.sample(pending, base, i), maxVal))
+		}
 
-		// Clamp to [-1.0, 1.0] to handle any potential overflow
-		if floatSamples[i] > 1.0 {
-			floatSamples[i] = 1.0
-		} else if floatSamples[i] < -1.0 {
-			floatSamples[i] = -1.0
+		// Drop source samples that no remaining output sample needs
+		consumed := r.firstTap(len(floatSamples)) - base
+		if consumed > len(pending) {
+			consumed = len(pending)
+		}
+		if consumed > 0 {
+			pending = append(pending[:0], pending[consumed:]...)
+			base += consumed
 		}
 	}
 
-	if verbose {
-		fmt.Printf("  Loaded %d samples (%.2f seconds)\n",
-			len(floatSamples), float64(len(floatSamples))/float64(targetRate))
+	// Match resample's output length; the final samples' filter windows run
+	// past the end of the audio
+	totalFrames := base + len(pending)
+	outputLen := r.outputLen(totalFrames)
+	if len(floatSamples) > outputLen {
+		floatSamples = floatSamples[:outputLen]
 	}
+	for len(floatSamples) < outputLen {
+		floatSamples = append(floatSamples, normalizeSample(r.sample(pending, base, len(floatSamples)), maxVal))
+	}
+
+	logLoaded(logger, len(floatSamples))
 
 	return floatSamples, nil
 }
 
-// resample performs simple linear interpolation resampling
-func resample(samples []int, fromRate, toRate int) []int {
-	if fromRate == toRate {
-		return samples
+// readRawPCM reads a stream of mono float32 little-endian samples
+func readRawPCM(r io.Reader) ([]float32, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio data: %w", err)
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("raw audio is %d bytes, not a whole number of float32 samples", len(data))
 	}
 
-	ratio := float64(fromRate) / float64(toRate)
-	outputLen := int(float64(len(samples)) / ratio)
-	resampled := make([]int, outputLen)
+	samples := make([]float32, len(data)/4)
+	for i := range samples {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return samples, nil
+}
 
-	for i := 0; i < outputLen; i++ {
-		srcPos := float64(i) * ratio
-		srcIdx := int(srcPos)
+// mixToMono averages interleaved channels into a single channel, or keeps
+// only the given 0-based channel if it is not negative
+// Samples are summed as int64 so that many channels of 32-bit audio cannot
+// overflow, and the average is rounded to the nearest integer.
+func mixToMono(data []int, numChannels, channel int) []int {
+	if numChannels <= 1 {
+		return data
+	}
 
-		if srcIdx >= len(samples)-1 {
-			resampled[i] = samples[len(samples)-1]
-			continue
+	monoSamples := make([]int, len(data)/numChannels)
+	if channel >= 0 {
+		for i := range monoSamples {
+			monoSamples[i] = data[i*numChannels+channel]
 		}
+		return monoSamples
+	}
+	for i := 0; i < len(monoSamples); i++ {
+		var sum int64
+		for ch := 0; ch < numChannels; ch++ {
+			sum += int64(data[i*numChannels+ch])
+		}
+		monoSamples[i] = int(math.Round(float64(sum) / float64(numChannels)))
+	}
+	return monoSamples
+}
 
-		// Linear interpolation
-		frac := srcPos - float64(srcIdx)
-		sample1 := float64(samples[srcIdx])
-		sample2 := float64(samples[srcIdx+1])
-		resampled[i] = int(sample1 + (sample2-sample1)*frac)
+// normalizationFactor returns the full-scale value for a bit depth
+// For 16-bit: max value is 2^15 (32768)
+// For 24-bit: max value is 2^23 (8388608)
+// For 32-bit (int or float): max value is 2^31 (2147483648)
+func normalizationFactor(bitDepth int) float32 {
+	switch bitDepth {
+	case 8:
+		return 128.0 // 2^7, after removing the unsigned bias
+	case 16:
+		return 32768.0 // 2^15
+	case 24:
+		return 8388608.0 // 2^23
+	case 32:
+		return 2147483648.0 // 2^31
+	default:
+		// Fallback for other bit depths
+		return float32(int64(1) << uint(bitDepth-1))
 	}
+}
 
-	return resampled
+// unbiasUnsigned8 centers 8-bit WAV samples on zero in place. 8-bit WAV PCM
+// is unsigned with silence at 128, unlike every other bit depth, so it must
+// be unbiased before mixing and normalizing. The decoder already returns
+// other depths, 24-bit included, as signed values.
+func unbiasUnsigned8(samples []int) {
+	for i := range samples {
+		samples[i] -= 128
+	}
 }
 
-// GetDefaultModelPath returns the default path for Whisper models
-func GetDefaultModelPath(modelName string) string {
+// normalizeSample converts an integer sample to float32 in [-1.0, 1.0]
+func normalizeSample(sample int, maxVal float32) float32 {
+	value := float32(sample) / maxVal
+
+	// Clamp to [-1.0, 1.0] to handle any potential overflow
+	if value > 1.0 {
+		return 1.0
+	} else if value < -1.0 {
+		return -1.0
+	}
+	return value
+}
+
+// Environment variables that override the default model directory, in order
+// of precedence
+const (
+	ModelDirEnv        = "PODCAST_TOOLS_MODEL_DIR"
+	WhisperModelDirEnv = "WHISPER_MODEL_DIR"
+)
+
+// GetDefaultModelDir returns the default directory for Whisper models. The
+// PODCAST_TOOLS_MODEL_DIR or WHISPER_MODEL_DIR environment variable is used
+// when set; otherwise models live in ~/.cache/whisper. It returns "" if no
+// variable is set and the home directory cannot be determined.
+func GetDefaultModelDir() string {
+	for _, env := range []string{ModelDirEnv, WhisperModelDirEnv} {
+		if dir := os.Getenv(env); dir != "" {
+			return dir
+		}
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(homeDir, ".cache", "whisper", fmt.Sprintf("ggml-%s.bin", modelName))
+	return filepath.Join(homeDir, ".cache", "whisper")
+}
+
+// GetDefaultModelPath returns the default path for Whisper models
+func GetDefaultModelPath(modelName string) string {
+	dir := GetDefaultModelDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, fmt.Sprintf("ggml-%s.bin", modelName))
+}
+
+// ModelName returns the model name for a model file path, the reverse of
+// GetDefaultModelPath (e.g., "/models/ggml-base.en.bin" is "base.en").
+// Files not following the ggml naming pattern return their base name.
+func ModelName(modelPath string) string {
+	name := filepath.Base(modelPath)
+	if strings.HasPrefix(name, "ggml-") && strings.HasSuffix(name, ".bin") {
+		return strings.TrimSuffix(strings.TrimPrefix(name, "ggml-"), ".bin")
+	}
+	return name
 }
@@ -0,0 +1,392 @@
+package transcriber
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
+	"github.com/go-audio/wav"
+)
+
+// wavHeader returns the 44-byte header of a PCM WAV file holding dataSize
+// bytes of samples
+func wavHeader(bitDepth, channels, sampleRate, dataSize int) []byte {
+	bytesPerSample := bitDepth / 8
+	header := make([]byte, 0, 44)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(36+dataSize))
+	header = append(header, "WAVEfmt "...)
+	header = binary.LittleEndian.AppendUint32(header, 16)
+	header = binary.LittleEndian.AppendUint16(header, 1) // PCM
+	header = binary.LittleEndian.AppendUint16(header, uint16(channels))
+	header = binary.LittleEndian.AppendUint32(header, uint32(sampleRate))
+	header = binary.LittleEndian.AppendUint32(header, uint32(sampleRate*channels*bytesPerSample))
+	header = binary.LittleEndian.AppendUint16(header, uint16(channels*bytesPerSample))
+	header = binary.LittleEndian.AppendUint16(header, uint16(bitDepth))
+	header = append(header, "data"...)
+	return binary.LittleEndian.AppendUint32(header, uint32(dataSize))
+}
+
+// writeTestWAV writes interleaved PCM samples as a WAV file in a temporary
+// directory and returns its path. Samples are written little-endian at the
+// given bit depth; 8-bit samples must already be unsigned (silence at 128).
+func writeTestWAV(t *testing.T, bitDepth, channels, sampleRate int, samples []int) string {
+	t.Helper()
+
+	bytesPerSample := bitDepth / 8
+	data := wavHeader(bitDepth, channels, sampleRate, len(samples)*bytesPerSample)
+	for _, sample := range samples {
+		for b := range bytesPerSample {
+			data = append(data, byte(sample>>(8*b)))
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "test.wav")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// discardLogger returns a logger that drops every record
+func discardLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// loadTestWAV decodes path both whole and in chunks, failing the test if
+// the two loaders disagree, and returns the samples
+func loadTestWAV(t *testing.T, path string) []float32 {
+	t.Helper()
+
+	whole, err := loadAudioFile(path, MixAverage, discardLogger())
+	if err != nil {
+		t.Fatalf("loadAudioFile: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	decoder := wav.NewDecoder(file)
+	if !decoder.IsValidFile() {
+		t.Fatal("invalid WAV file")
+	}
+	streamed, err := loadAudioStream(decoder, MixAverage, discardLogger())
+	if err != nil {
+		t.Fatalf("loadAudioStream: %v", err)
+	}
+
+	if len(whole) != len(streamed) {
+		t.Fatalf("loadAudioFile returned %d samples, loadAudioStream %d", len(whole), len(streamed))
+	}
+	for i := range whole {
+		if whole[i] != streamed[i] {
+			t.Fatalf("sample %d: loadAudioFile %g, loadAudioStream %g", i, whole[i], streamed[i])
+		}
+	}
+	return whole
+}
+
+func TestLoadAudio24BitSigned(t *testing.T) {
+	// Two's-complement 24-bit samples as they appear in the file
+	samples := []int{0x000000, 0x7FFFFF, 0x800000, 0xC00000, 0x400000}
+	want := []float32{0, 1, -1, -0.5, 0.5}
+
+	got := loadTestWAV(t, writeTestWAV(t, 24, 1, 16000, samples))
+	if len(got) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("sample %d (%#06x) = %g, want %g", i, samples[i], got[i], want[i])
+		}
+	}
+}
+
+var benchWAVHours = flag.Float64("bench-wav-hours", 2, "Length in hours of the WAV file BenchmarkLoadWAVPeakMemory decodes")
+
+// writeLongWAV writes hours of a 16-bit 44.1kHz mono 440Hz tone to path a
+// second at a time, so generating it needs little memory itself
+func writeLongWAV(path string, hours float64) error {
+	const rate = 44100
+	seconds := int(hours * 3600)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	w.Write(wavHeader(16, 1, rate, seconds*rate*2))
+	second := make([]byte, 0, rate*2)
+	for i := range rate {
+		v := int16(8000 * math.Sin(2*math.Pi*440*float64(i)/rate))
+		second = binary.LittleEndian.AppendUint16(second, uint16(v))
+	}
+	for range seconds {
+		w.Write(second)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// peakHeap runs f and returns the largest heap in use above the level before
+// it started, sampled every few milliseconds
+func peakHeap(f func()) uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	baseline := stats.HeapInuse
+
+	var peak atomic.Uint64
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		var stats runtime.MemStats
+		for {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapInuse > baseline && stats.HeapInuse-baseline > peak.Load() {
+				peak.Store(stats.HeapInuse - baseline)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	f()
+	close(done)
+	<-sampled
+	return peak.Load()
+}
+
+// BenchmarkLoadWAVPeakMemory compares the peak heap of decoding a long WAV
+// file in chunks with loadAudioStream against decoding it whole with
+// loadWAVBuffered, reported as peak-MB. The file is two hours long by
+// default. The buffered path needs roughly 900MB per 15 minutes of audio,
+// so pass -args -bench-wav-hours 0.25 on machines without 8GB to spare.
+func BenchmarkLoadWAVPeakMemory(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "long.wav")
+	if err := writeLongWAV(path, *benchWAVHours); err != nil {
+		b.Fatal(err)
+	}
+
+	loaders := []struct {
+		name string
+		load func(*wav.Decoder, ChannelMix, *slog.Logger) ([]float32, error)
+	}{
+		{"stream", loadAudioStream},
+		{"buffered", loadWAVBuffered},
+	}
+	for _, loader := range loaders {
+		b.Run(loader.name, func(b *testing.B) {
+			var peak uint64
+			for b.Loop() {
+				file, err := os.Open(path)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decoder := wav.NewDecoder(file)
+				peak = max(peak, peakHeap(func() {
+					if _, err := loader.load(decoder, MixAverage, discardLogger()); err != nil {
+						b.Error(err)
+					}
+				}))
+				file.Close()
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-MB")
+		})
+	}
+}
+
+func TestMixToMono32Bit6Channel(t *testing.T) {
+	const maxInt32, minInt32 = math.MaxInt32, math.MinInt32
+	tests := []struct {
+		name  string
+		frame []int
+		want  int
+	}{
+		// A naive int32 sum would overflow on these
+		{"all full scale positive", []int{maxInt32, maxInt32, maxInt32, maxInt32, maxInt32, maxInt32}, maxInt32},
+		{"all full scale negative", []int{minInt32, minInt32, minInt32, minInt32, minInt32, minInt32}, minInt32},
+		{"cancelling", []int{maxInt32, minInt32, maxInt32, minInt32, maxInt32, minInt32}, -1}, // -3/6 = -0.5 rounds away from zero
+		{"rounds up", []int{1, 1, 1, 1, 0, 0}, 1},                                             // 4/6
+		{"rounds down", []int{1, 1, 0, 0, 0, 0}, 0},                                           // 2/6
+		{"half rounds away from zero", []int{1, 1, 1, 0, 0, 0}, 1},                            // 3/6
+		{"negative half rounds away from zero", []int{-1, -1, -1, 0, 0, 0}, -1},               // -3/6
+		{"mixed", []int{1000000000, 2000000000, -500000000, 7, 11, -3}, 416666669},            // 2500000015/6 = 416666669.17
+	}
+
+	// Interleave every test frame into one buffer, as the decoder returns it
+	var data []int
+	for _, tt := range tests {
+		data = append(data, tt.frame...)
+	}
+	mono := mixToMono(data, 6, -1)
+	if len(mono) != len(tests) {
+		t.Fatalf("got %d mono samples, want %d", len(mono), len(tests))
+	}
+	for i, tt := range tests {
+		if mono[i] != tt.want {
+			t.Errorf("%s: mixToMono = %d, want %d", tt.name, mono[i], tt.want)
+		}
+	}
+}
+
+func TestMixToMonoChannel(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	got := mixToMono(data, 6, 4)
+	if len(got) != 2 || got[0] != 5 || got[1] != 11 {
+		t.Errorf("mixToMono(channel 4) = %v, want [5 11]", got)
+	}
+}
+
+func TestLoadAudio8BitUnsigned(t *testing.T) {
+	// Unsigned 8-bit samples as they appear in the file, silence at 128
+	samples := []int{128, 128, 255, 0, 192, 64}
+	want := []float32{0, 0, 127.0 / 128, -1, 0.5, -0.5}
+
+	got := loadTestWAV(t, writeTestWAV(t, 8, 1, 16000, samples))
+	if len(got) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("sample %d (%d) = %g, want %g", i, samples[i], got[i], want[i])
+		}
+	}
+}
+
+func TestNewWhisperTranscriberRejectsBeamSize(t *testing.T) {
+	// The beam width is checked before the model is loaded
+	_, err := NewWhisperTranscriber(WhisperConfig{ModelPath: "missing.bin", BeamSize: 5})
+	if !errors.Is(err, errBeamSearch) {
+		t.Errorf("NewWhisperTranscriber(BeamSize: 5) error = %v, want %v", err, errBeamSearch)
+	}
+}
+
+func TestGetDefaultModelDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the home directory is not read from HOME on", runtime.GOOS)
+	}
+	tests := []struct {
+		name       string
+		env        string // PODCAST_TOOLS_MODEL_DIR
+		whisperEnv string // WHISPER_MODEL_DIR
+		home       string
+		want       string
+	}{
+		{"env set", "/mnt/models", "", "/home/alice", "/mnt/models"},
+		{"whisper env set", "", "/mnt/whisper", "/home/alice", "/mnt/whisper"},
+		{"own env wins", "/mnt/models", "/mnt/whisper", "/home/alice", "/mnt/models"},
+		{"env unset", "", "", "/home/alice", filepath.Join("/home/alice", ".cache", "whisper")},
+		{"no home directory", "", "", "", ""},
+		{"env set without home directory", "/mnt/models", "", "", "/mnt/models"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ModelDirEnv, tt.env)
+			t.Setenv(WhisperModelDirEnv, tt.whisperEnv)
+			t.Setenv("HOME", tt.home)
+
+			if got := GetDefaultModelDir(); got != tt.want {
+				t.Errorf("GetDefaultModelDir() = %q, want %q", got, tt.want)
+			}
+			wantPath := ""
+			if tt.want != "" {
+				wantPath = filepath.Join(tt.want, "ggml-base.en.bin")
+			}
+			if got := GetDefaultModelPath("base.en"); got != wantPath {
+				t.Errorf("GetDefaultModelPath(base.en) = %q, want %q", got, wantPath)
+			}
+		})
+	}
+}
+
+// fakeModel is a whisper.Model that reports language support without
+// loading anything
+type fakeModel struct {
+	multilingual bool
+}
+
+func (m fakeModel) Close() error { return nil }
+
+func (m fakeModel) NewContext() (whisper.Context, error) {
+	return nil, errors.New("fakeModel cannot transcribe")
+}
+
+func (m fakeModel) IsMultilingual() bool { return m.multilingual }
+
+func (m fakeModel) Languages() []string {
+	if m.multilingual {
+		return []string{"en", "de", "fr"}
+	}
+	return []string{"en"}
+}
+
+// recordingHandler is a slog.Handler that keeps the messages of the records
+// it handles
+type recordingHandler struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestLogModelLanguages(t *testing.T) {
+	const forced = "Model is English-only, language forced to English (auto-detection unavailable)"
+	const noTranslate = "Model is English-only, translation is unavailable"
+	tests := []struct {
+		name         string
+		multilingual bool
+		language     string
+		translate    bool
+		want         []string
+	}{
+		{"English-only model with auto language", false, "auto", false, []string{forced}},
+		{"English-only model with explicit language", false, "en", false, nil},
+		{"English-only model translating", false, "auto", true, []string{forced, noTranslate}},
+		{"multilingual model", true, "auto", true, []string{"Multilingual model detected"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &recordingHandler{}
+			config := WhisperConfig{Language: tt.language, Translate: tt.translate}
+			logModelLanguages(slog.New(handler), fakeModel{multilingual: tt.multilingual}, config)
+
+			if !slices.Equal(handler.messages, tt.want) {
+				t.Errorf("logged %q, want %q", handler.messages, tt.want)
+			}
+		})
+	}
+}